@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/uforg/ufocontract/internal/ufoc/fake"
+)
+
+var fakeCommand = &command{
+	name:    "fake",
+	summary: "print random JSON instances of a type",
+	run:     runFake,
+}
+
+func runFake(e *env, args []string) error {
+	fs := newFlagSet(e, "fake", "[flags] <Namespace.Type>")
+	file := contractFlag(fs)
+	count := fs.Int("count", 1, "number of instances, printed one per line")
+	seed := fs.Uint64("seed", 1, "random seed, the same seed prints the same instances")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *count < 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	schema, err := loadContract(e, *file)
+	if err != nil {
+		return err
+	}
+	ns, t, err := lookupType(schema, positional[0])
+	if err != nil {
+		return err
+	}
+
+	g := fake.New(schema, *seed)
+	for range *count {
+		v, err := g.Value(ns, t)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "%s\n", v)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/uforg/ufocontract/internal/ufoc/importer"
+)
+
+var importCommand = &command{
+	name:    "import",
+	summary: "generate a .ufoc file from another schema language",
+	run:     runImport,
+}
+
+// importers are the source languages of ufoc import.
+var importers = map[string]func(e *env, args []string) error{
+	"jsonschema": runImportJSONSchema,
+	"go":         runImportGo,
+}
+
+func runImport(e *env, args []string) error {
+	if len(args) > 0 {
+		if run, ok := importers[args[0]]; ok {
+			return run(e, args[1:])
+		}
+		fmt.Fprintf(e.stderr, "ufoc import: unknown source %q\n", args[0])
+	}
+	fmt.Fprintln(e.stderr, "Usage: ufoc import <source> [arguments]")
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "Sources:")
+	fmt.Fprintln(e.stderr, "  jsonschema  a JSON Schema file")
+	fmt.Fprintln(e.stderr, "  go          the structs and enums of a Go package")
+	return errUsage
+}
+
+func runImportJSONSchema(e *env, args []string) error {
+	fs := newFlagSet(e, "import jsonschema", "[flags] --namespace <Namespace> <schema.json>")
+	namespace, out := importFlags(fs)
+	name := fs.String("name", "", "`name` of the type of the root schema (default: its title)")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *namespace == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	data, err := os.ReadFile(positional[0])
+	if err != nil {
+		return err
+	}
+	file, err := importer.JSONSchema(data, *namespace, *name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", positional[0], err)
+	}
+	file.Comment = fmt.Sprintf("Imported from %s by ufoc import jsonschema.", positional[0])
+	return writeImport(e, "import", file, *out)
+}
+
+func runImportGo(e *env, args []string) error {
+	fs := newFlagSet(e, "import go", "[flags] --namespace <Namespace> <package directory>")
+	namespace, out := importFlags(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *namespace == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	file, err := importer.Go(positional[0], *namespace)
+	if err != nil {
+		return fmt.Errorf("%s: %w", positional[0], err)
+	}
+	file.Comment = fmt.Sprintf("Imported from %s by ufoc import go.", positional[0])
+	return writeImport(e, "import", file, *out)
+}
+
+// importFlags adds the flags shared by the importers: the namespace of the
+// imported definitions and the output file.
+func importFlags(fs *flag.FlagSet) (namespace, out *string) {
+	namespace = fs.String("namespace", "", "`name` of the namespace holding the imported definitions")
+	out = fs.String("out", "", "output `file` (default: standard output)")
+	return namespace, out
+}
+
+// writeImport prints an imported file to out, or to stdout when out is empty,
+// and reports the number of warnings it holds as the command with the name.
+func writeImport(e *env, name string, file *importer.File, out string) error {
+	src := importer.Format(file)
+	if out == "" {
+		if _, err := e.stdout.Write(src); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(out, src, 0o644); err != nil {
+		return err
+	}
+
+	switch n := file.WarningCount(); n {
+	case 0:
+	case 1:
+		fmt.Fprintf(e.stderr, "ufoc %s: 1 warning, see the WARNING comment\n", name)
+	default:
+		fmt.Fprintf(e.stderr, "ufoc %s: %d warnings, see the WARNING comments\n", name, n)
+	}
+	return nil
+}
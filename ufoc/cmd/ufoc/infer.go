@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/uforg/ufocontract/internal/ufoc/importer"
+)
+
+var inferCommand = &command{
+	name:    "infer",
+	summary: "generate a .ufoc type from sample JSON documents",
+	run:     runInfer,
+}
+
+func runInfer(e *env, args []string) error {
+	fs := newFlagSet(e, "infer", "[flags] --name <Type> [sample.json ...]")
+	name := fs.String("name", "", "`name` of the inferred type")
+	namespace := fs.String("namespace", "", "`name` of the namespace holding the inferred definitions (default: the type name)")
+	out := fs.String("out", "", "output `file` (default: standard output)")
+	inputs, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		fs.Usage()
+		return errUsage
+	}
+	if *namespace == "" {
+		*namespace = *name
+	}
+
+	var samples [][]byte
+	if len(inputs) == 0 {
+		data, err := io.ReadAll(e.stdin)
+		if err != nil {
+			return err
+		}
+		if samples, err = readSamples("stdin", data, samples); err != nil {
+			return err
+		}
+	}
+	for _, path := range inputs {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if samples, err = readSamples(path, data, samples); err != nil {
+			return err
+		}
+	}
+
+	file, err := importer.Infer(samples, *namespace, *name)
+	if err != nil {
+		return err
+	}
+	file.Comment = fmt.Sprintf("Inferred from %d samples by ufoc infer.", len(samples))
+	return writeImport(e, "infer", file, *out)
+}
+
+// readSamples appends the documents of an input, a single JSON object or
+// newline-delimited ones, to samples.
+func readSamples(name string, data []byte, samples [][]byte) ([][]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc json.RawMessage
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid JSON: %w", name, err)
+		}
+		if doc[0] != '{' {
+			start := int(dec.InputOffset()) - len(doc)
+			line := 1 + bytes.Count(data[:start], []byte("\n"))
+			return nil, fmt.Errorf("%s:%d: the sample is not a JSON object", name, line)
+		}
+		samples = append(samples, doc)
+	}
+}
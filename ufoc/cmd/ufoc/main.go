@@ -0,0 +1,172 @@
+// Command ufoc is the UFO Contract compiler.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/uforg/ufocontract/internal/ufoc/analyzer"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+	"github.com/uforg/ufocontract/internal/ufoc/loader"
+)
+
+// command is a ufoc subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(env *env, args []string) error
+}
+
+var commands = []*command{
+	fakeCommand,
+	validateCommand,
+	importCommand,
+	inferCommand,
+}
+
+// env holds the standard streams of a command, so commands can be tested
+// without a process.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// errUsage is returned by commands called with invalid arguments, after the
+// problem has been printed.
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(&env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}, os.Args[1:]))
+}
+
+// run runs the command line args and returns the exit code: 0 on success, 1
+// when the command fails and 2 for usage errors.
+func run(e *env, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(e.stderr)
+		return 2
+	}
+
+	i := slices.IndexFunc(commands, func(c *command) bool { return c.name == args[0] })
+	if i < 0 {
+		fmt.Fprintf(e.stderr, "ufoc: unknown command %q\n", args[0])
+		usage(e.stderr)
+		return 2
+	}
+
+	err := commands[i].run(e, args[1:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return 2
+	default:
+		fmt.Fprintf(e.stderr, "ufoc %s: %s\n", args[0], err)
+		return 1
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: ufoc <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+}
+
+// newFlagSet returns the flag set of a command, printing its usage line and
+// flags to the stderr of e.
+func newFlagSet(e *env, name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: ufoc %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses args with fs, allowing flags after the positional
+// arguments (ufoc fake Tasks.Task --count 10), and returns the positional
+// arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// contractFlag adds the --file flag selecting the contract file.
+func contractFlag(fs *flag.FlagSet) *string {
+	return fs.String("file", "", "contract `file` (default: the only .ufoc file in the current directory)")
+}
+
+// loadContract loads and analyzes the contract file at path, or the only
+// .ufoc file of the current directory when path is empty. Diagnostics are
+// printed to stderr, and errors among them make loading fail.
+func loadContract(e *env, path string) (*ir.Schema, error) {
+	path, err := contractPath(path)
+	if err != nil {
+		return nil, err
+	}
+	res, err := loader.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	diags := res.Diagnostics
+	if !diagnostic.HasErrors(diags) {
+		diags = append(diags, analyzer.Analyze(res.Schema, analyzer.Config{})...)
+	}
+	for _, d := range diags {
+		fmt.Fprintln(e.stderr, d)
+	}
+	if diagnostic.HasErrors(diags) {
+		return nil, fmt.Errorf("%s has errors", path)
+	}
+	return res.Schema, nil
+}
+
+// contractPath returns path, or the only .ufoc file of the current directory
+// when path is empty.
+func contractPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	matches, err := filepath.Glob("*.ufoc")
+	if err != nil {
+		return "", err
+	}
+	if len(matches) != 1 {
+		return "", fmt.Errorf("found %d .ufoc files in the current directory, select one with --file", len(matches))
+	}
+	return matches[0], nil
+}
+
+// lookupType returns the type with the qualified name, e.g. Tasks.Task.
+func lookupType(schema *ir.Schema, name string) (*ir.Namespace, *ir.Type, error) {
+	ns, t := schema.LookupType(name)
+	if t == nil {
+		if !strings.Contains(name, ".") {
+			return nil, nil, fmt.Errorf("type %q must be qualified by its namespace, e.g. Tasks.%s", name, name)
+		}
+		return nil, nil, fmt.Errorf("unknown type %q", name)
+	}
+	return ns, t, nil
+}
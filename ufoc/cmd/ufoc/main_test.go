@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runCmd runs the command line and returns its exit code, stdout and stderr.
+func runCmd(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(&env{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}, args)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeContract(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "contract.ufoc")
+	require.NoError(t, os.WriteFile(path, []byte(src), 0o644))
+	return path
+}
+
+func TestRunUsage(t *testing.T) {
+	code, _, stderr := runCmd(t, "")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "Usage: ufoc <command>")
+
+	code, _, stderr = runCmd(t, "", "compile")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown command "compile"`)
+}
+
+func TestRunContractErrors(t *testing.T) {
+	path := writeContract(t, "version 1\nnamespace Tasks { type Task { id: Missing } }")
+
+	code, _, stderr := runCmd(t, "", "fake", "--file", path, "Tasks.Task")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown type "Missing"`)
+	assert.Contains(t, stderr, "has errors")
+}
+
+func TestRunFake(t *testing.T) {
+	path := writeContract(t, "version 1\nnamespace Tasks { type Task { id: uuid\ntitle: string } }")
+
+	code, stdout, stderr := runCmd(t, "", "fake", "Tasks.Task", "--file", path, "--count", "3", "--seed", "42")
+	require.Equal(t, 0, code, stderr)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], `{"id":"`))
+
+	_, again, _ := runCmd(t, "", "fake", "--file", path, "--count", "3", "--seed", "42", "Tasks.Task")
+	assert.Equal(t, stdout, again)
+
+	code, _, stderr = runCmd(t, "", "fake", "--file", path, "Tasks.Missing")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown type "Tasks.Missing"`)
+
+	code, _, stderr = runCmd(t, "", "fake", "--file", path, "Task")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "must be qualified by its namespace")
+
+	code, _, _ = runCmd(t, "", "fake", "--file", path)
+	assert.Equal(t, 2, code)
+}
+
+func TestRunValidate(t *testing.T) {
+	path := writeContract(t, "version 1\nnamespace Tasks { type Task { id: uuid\ntitle: string } }")
+	valid := `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "title": "Ship"}`
+
+	code, stdout, stderr := runCmd(t, valid+"\n"+valid+"\n", "validate", "--file", path, "--type", "Tasks.Task")
+	assert.Equal(t, 0, code, stderr)
+	assert.Empty(t, stdout)
+
+	payload := filepath.Join(t.TempDir(), "payload.json")
+	require.NoError(t, os.WriteFile(payload, []byte(valid+"\n\n"+`{"id": "x", "title": "Ship", "done": true}`), 0o644))
+	code, stdout, stderr = runCmd(t, "", "validate", payload, "--file", path, "--type", "Tasks.Task")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, payload+`:3: /id: "x" is not a valid uuid (`+path+`:2:31)`+"\n"+
+		payload+`:3: /done: unknown field "done" (`+path+`:2:19)`+"\n", stdout)
+	assert.Contains(t, stderr, "1 of 2 documents are invalid")
+
+	code, _, stderr = runCmd(t, valid+"\n{", "validate", "--file", path, "--type", "Tasks.Task")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "stdin: invalid JSON: unexpected EOF")
+	assert.Contains(t, stderr, "1 of 2 documents are invalid")
+
+	code, _, _ = runCmd(t, "", "validate", "--file", path)
+	assert.Equal(t, 2, code)
+}
+
+func TestRunImportJSONSchema(t *testing.T) {
+	dir := t.TempDir()
+	schema := filepath.Join(dir, "task.json")
+	require.NoError(t, os.WriteFile(schema, []byte(`{
+		"title": "task",
+		"properties": {"id": {"type": "string", "format": "uuid"}, "email": {"type": "string", "format": "email"}},
+		"required": ["id"]
+	}`), 0o644))
+
+	code, stdout, stderr := runCmd(t, "", "import", "jsonschema", schema, "--namespace", "Tasks")
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "namespace Tasks {\n")
+	assert.Contains(t, stdout, "  type Task {\n    id: uuid\n    email?: string\n  }\n")
+	assert.Contains(t, stderr, "1 warning, see the WARNING comment")
+
+	out := filepath.Join(dir, "tasks.ufoc")
+	code, _, stderr = runCmd(t, "", "import", "jsonschema", "--namespace", "Tasks", "--name", "Item", "--out", out, schema)
+	require.Equal(t, 0, code, stderr)
+	code, _, stderr = runCmd(t, "", "fake", "--file", out, "Tasks.Item")
+	assert.Equal(t, 0, code, stderr)
+
+	code, _, _ = runCmd(t, "", "import", "jsonschema", schema)
+	assert.Equal(t, 2, code)
+	code, _, stderr = runCmd(t, "", "import", "xsd", schema)
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, `unknown source "xsd"`)
+}
+
+func TestRunImportGo(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/tasks\n\ngo 1.25\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tasks.go"), []byte(`package tasks
+
+import "time"
+
+// Task is a unit of work.
+type Task struct {
+	ID      string            `+"`json:\"id\"`"+`
+	Due     *time.Time        `+"`json:\"due\"`"+`
+	Labels  map[string]string `+"`json:\"labels\"`"+`
+}
+`), 0o644))
+
+	code, stdout, stderr := runCmd(t, "", "import", "go", "--namespace", "Tasks", dir)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "  \"\"\" Task is a unit of work. \"\"\"\n  type Task {\n    id: string\n    due: datetime | null\n  }\n")
+	assert.Contains(t, stderr, "1 warning, see the WARNING comment")
+
+	out := filepath.Join(t.TempDir(), "tasks.ufoc")
+	code, _, stderr = runCmd(t, "", "import", "go", "--namespace", "Tasks", "--out", out, dir)
+	require.Equal(t, 0, code, stderr)
+	code, _, stderr = runCmd(t, "", "fake", "--file", out, "Tasks.Task")
+	assert.Equal(t, 0, code, stderr)
+
+	code, _, _ = runCmd(t, "", "import", "go", dir)
+	assert.Equal(t, 2, code)
+}
+
+func TestRunInfer(t *testing.T) {
+	dir := t.TempDir()
+	samples := filepath.Join(dir, "events.ndjson")
+	require.NoError(t, os.WriteFile(samples, []byte(`{"id": 1, "at": "2024-05-01T10:00:00Z", "kind": "created"}
+{"id": 2, "at": "2024-05-02T10:00:00Z", "kind": "paid", "note": "x"}
+{"id": 3, "at": "2024-05-03T10:00:00Z", "kind": "paid"}
+{"id": 4, "at": "2024-05-04T10:00:00Z", "kind": "created"}
+`), 0o644))
+
+	code, stdout, stderr := runCmd(t, "", "infer", "--name", "Event", samples)
+	require.Equal(t, 0, code, stderr)
+	assert.Contains(t, stdout, "// Inferred from 4 samples by ufoc infer.\n")
+	assert.Contains(t, stdout, "namespace Event {\n")
+	assert.Contains(t, stdout, "  type Event {\n    id: int\n    at: datetime\n    kind: EventKind\n    note?: string\n  }\n")
+	assert.Contains(t, stderr, "ufoc infer: 1 warning, see the WARNING comment")
+
+	out := filepath.Join(dir, "events.ufoc")
+	code, _, stderr = runCmd(t, `{"id": 1}`, "infer", "--name", "Event", "--namespace", "Events", "--out", out)
+	require.Equal(t, 0, code, stderr)
+	code, _, stderr = runCmd(t, "", "fake", "--file", out, "Events.Event")
+	assert.Equal(t, 0, code, stderr)
+
+	code, _, stderr = runCmd(t, "{\"id\": 1}\n[1]\n", "infer", "--name", "Event")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "stdin:2: the sample is not a JSON object")
+
+	code, _, _ = runCmd(t, "", "infer", samples)
+	assert.Equal(t, 2, code)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/uforg/ufocontract/validate"
+)
+
+var validateCommand = &command{
+	name:    "validate",
+	summary: "validate JSON documents against a type",
+	run:     runValidate,
+}
+
+func runValidate(e *env, args []string) error {
+	fs := newFlagSet(e, "validate", "[flags] --type <Namespace.Type> [file.json ...]")
+	file := contractFlag(fs)
+	typeName := fs.String("type", "", "qualified `name` of the type documents are validated against")
+	inputs, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *typeName == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	path, err := contractPath(*file)
+	if err != nil {
+		return err
+	}
+	contract, err := validate.Load(path)
+	if err != nil {
+		return err
+	}
+	v, err := contract.Validator(*typeName)
+	if err != nil {
+		return err
+	}
+
+	var total, invalid int
+	check := func(name string, data []byte) {
+		n, bad := validateDocuments(e, v, name, data)
+		total += n
+		invalid += bad
+	}
+	if len(inputs) == 0 {
+		data, err := io.ReadAll(e.stdin)
+		if err != nil {
+			return err
+		}
+		check("stdin", data)
+	}
+	for _, path := range inputs {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		check(path, data)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d documents are invalid", invalid, total)
+	}
+	return nil
+}
+
+// validateDocuments validates the documents of an input, a single JSON
+// document or newline-delimited ones, printing their violations prefixed
+// with the input name and the line of the document. It returns the number
+// of documents and of invalid ones, counting JSON syntax errors, which stop
+// reading the input.
+func validateDocuments(e *env, v *validate.Validator, name string, data []byte) (total, invalid int) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc json.RawMessage
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return total, invalid
+		}
+		total++
+		if err != nil {
+			fmt.Fprintf(e.stderr, "%s: invalid JSON: %s\n", name, err)
+			return total, invalid + 1
+		}
+
+		start := int(dec.InputOffset()) - len(doc)
+		line := 1 + bytes.Count(data[:start], []byte("\n"))
+		violations, err := v.Validate(doc)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "%s:%d: %s\n", name, line, err)
+			invalid++
+			continue
+		}
+		for _, violation := range violations {
+			fmt.Fprintf(e.stdout, "%s:%d: %s\n", name, line, violation)
+		}
+		if len(violations) > 0 {
+			invalid++
+		}
+	}
+}
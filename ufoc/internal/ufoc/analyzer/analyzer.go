@@ -0,0 +1,111 @@
+// Package analyzer checks a resolved contract for problems that the grammar
+// alone cannot catch and reports them as diagnostics.
+package analyzer
+
+import (
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// Rule identifies a configurable analyzer check.
+type Rule string
+
+const (
+	RuleTypeNaming        Rule = "naming/type"
+	RuleEnumNaming        Rule = "naming/enum"
+	RuleEnumMemberNaming  Rule = "naming/enum-member"
+	RuleConstNaming       Rule = "naming/const"
+	RulePatternNaming     Rule = "naming/pattern"
+	RuleFieldNaming       Rule = "naming/field"
+	RulePlaceholderNaming Rule = "naming/placeholder"
+)
+
+// defaultSeverities are the severities used for rules not set in Config.
+var defaultSeverities = map[Rule]diagnostic.Severity{
+	RuleTypeNaming:        diagnostic.SeverityWarning,
+	RuleEnumNaming:        diagnostic.SeverityWarning,
+	RuleEnumMemberNaming:  diagnostic.SeverityWarning,
+	RuleConstNaming:       diagnostic.SeverityWarning,
+	RulePatternNaming:     diagnostic.SeverityWarning,
+	RuleFieldNaming:       diagnostic.SeverityWarning,
+	RulePlaceholderNaming: diagnostic.SeverityWarning,
+}
+
+// Config configures the analyzer. The zero value uses the default severity
+// for every rule.
+type Config struct {
+	// Severities overrides the severity of individual rules.
+	Severities map[Rule]diagnostic.Severity
+}
+
+func (c Config) severity(rule Rule) diagnostic.Severity {
+	if s, ok := c.Severities[rule]; ok {
+		return s
+	}
+	return defaultSeverities[rule]
+}
+
+// Analyze checks the schema and returns the diagnostics found. It also
+// resolves the references in the schema, completing the resolved model.
+func Analyze(schema *ir.Schema, cfg Config) []diagnostic.Diagnostic {
+	a := &analyzer{cfg: cfg}
+	a.checkFileOptions(schema.Options)
+	a.checkNamespaces(schema.Namespaces)
+
+	// Every namespace is declared and resolved before any is checked, since
+	// definitions can refer to the ones of other namespaces.
+	namespaces := schema.AllNamespaces()
+	scopes := map[*ir.Namespace]*scope{}
+	a.declareNamespaces(&scope{}, schema.Namespaces, scopes)
+	pending := map[*ir.Namespace][]*ir.TypeRef{}
+	for _, ns := range namespaces {
+		a.resolveNamespace(scopes[ns])
+		pending[ns], a.pending = a.pending, nil
+	}
+	for _, ns := range namespaces {
+		a.checkSpreads(ns)
+		a.pending = pending[ns]
+		a.instantiate(scopes[ns], ns)
+	}
+	a.fieldOwners = schema.FieldNamespaces()
+	e := newEvaluator(a, scopes)
+	for _, ns := range namespaces {
+		a.checkValues(e, scopes[ns])
+		a.checkPatterns(scopes[ns], ns)
+		a.checkExposure(ns)
+		a.checkAnnotations(ns)
+		a.checkEnums(e, ns)
+	}
+	// Renames fix the references of every namespace, which are only all
+	// resolved once the values are folded.
+	refs := collectRefs(namespaces)
+	for _, ns := range namespaces {
+		a.checkNaming(ns, refs)
+	}
+	return a.diags
+}
+
+type analyzer struct {
+	cfg   Config
+	diags []diagnostic.Diagnostic
+	// pending holds the generic references of the namespace being resolved or
+	// instantiated waiting to be instantiated.
+	pending []*ir.TypeRef
+	// fieldOwners maps every field to the namespace declaring it.
+	fieldOwners map[*ir.Field]*ir.Namespace
+}
+
+func (a *analyzer) errorf(pos lexer.Position, format string, args ...any) {
+	a.diags = append(a.diags, diagnostic.Errorf(pos, format, args...))
+}
+
+func (a *analyzer) report(rule Rule, pos lexer.Position, msg string, fix *diagnostic.Fix) {
+	a.diags = append(a.diags, diagnostic.Diagnostic{
+		Pos:      pos,
+		Severity: a.cfg.severity(rule),
+		Message:  msg,
+		Rule:     string(rule),
+		Fix:      fix,
+	})
+}
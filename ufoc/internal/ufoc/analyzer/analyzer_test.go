@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+	"github.com/uforg/ufocontract/internal/ufoc/loader"
+)
+
+func TestAnalyzeValidContract(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum TaskStatus {
+				PENDING
+				IN_PROGRESS
+			}
+
+			type Task {
+				id: string
+				status: TaskStatus
+				meta: { createdBy: string }
+			}
+
+			const MaxRetries: int = 5
+
+			pattern TaskTopic = "{ns}.{taskId}.updates"
+		}
+	`
+
+	assert.Empty(t, analyze(t, input, Config{}))
+}
+
+func TestAnalyzeSeverityOverrides(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type task {
+				Id: string
+			}
+		}
+	`
+
+	diags := analyze(t, input, Config{})
+	require.Len(t, diags, 2)
+	assert.Equal(t, diagnostic.SeverityWarning, diags[0].Severity)
+	assert.Equal(t, diagnostic.SeverityWarning, diags[1].Severity)
+
+	diags = analyze(t, input, Config{Severities: map[Rule]diagnostic.Severity{
+		RuleTypeNaming: diagnostic.SeverityError,
+	}})
+	require.Len(t, diags, 2)
+	assert.Equal(t, diagnostic.SeverityError, diags[0].Severity)
+	assert.Equal(t, string(RuleTypeNaming), diags[0].Rule)
+	assert.Equal(t, diagnostic.SeverityWarning, diags[1].Severity)
+	assert.Equal(t, string(RuleFieldNaming), diags[1].Rule)
+}
+
+/*******************
+* HELPER FUNCTIONS *
+*******************/
+
+func analyze(t *testing.T, input string, cfg Config) []diagnostic.Diagnostic {
+	t.Helper()
+
+	res, err := loader.LoadBytes(filepath.Join(t.TempDir(), "test.ufoc"), []byte(input))
+	require.NoError(t, err)
+	require.Empty(t, res.Diagnostics)
+
+	return Analyze(res.Schema, cfg)
+}
+
+func resolve(t *testing.T, input string) *ir.Schema {
+	t.Helper()
+
+	res, err := loader.LoadBytes(filepath.Join(t.TempDir(), "test.ufoc"), []byte(input))
+	require.NoError(t, err)
+	require.Empty(t, Analyze(res.Schema, Config{}))
+
+	return res.Schema
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+	"github.com/uforg/ufocontract/internal/ufoc/reserved"
+)
+
+// annotationSpec describes a built-in annotation: what it can be used on,
+// the arguments it takes and how its value is validated.
+type annotationSpec struct {
+	// on is the only kind of element the annotation can be used on, empty when
+	// it can be used on any.
+	on   string
+	args annotationArgs
+	// valid checks the value of annotations taking a single string; internal
+	// is set for internal definitions. It returns a description of the
+	// expected value when the value is invalid. It is nil when any string is
+	// valid.
+	valid func(value string, internal bool) (string, bool)
+}
+
+// annotationArgs is the kind of arguments a built-in annotation takes.
+type annotationArgs int
+
+const (
+	argString annotationArgs = iota
+	argNone
+	// argValues are one or more literals, checked by the element the
+	// annotation is used on.
+	argValues
+)
+
+var tsIdentRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+var annotationSpecs = map[string]annotationSpec{
+	ir.AnnotationGoName: {valid: func(v string, internal bool) (string, bool) {
+		if internal {
+			return "a Go identifier", identRe.MatchString(v) && !reserved.Go.IsReserved(v)
+		}
+		return "an exported Go identifier", identRe.MatchString(v) && unicode.IsUpper(rune(v[0]))
+	}},
+	ir.AnnotationTSName: {valid: func(v string, _ bool) (string, bool) {
+		return "a TypeScript identifier", tsIdentRe.MatchString(v) && !reserved.TypeScript.IsReserved(v)
+	}},
+	ir.AnnotationPythonName: {valid: func(v string, _ bool) (string, bool) {
+		return "a Python identifier", identRe.MatchString(v) && !reserved.Python.IsReserved(v)
+	}},
+	ir.AnnotationGoType: {on: "field", valid: nonEmpty("a Go type")},
+	ir.AnnotationTSType: {on: "field", valid: nonEmpty("a TypeScript type")},
+	// The empty string is a valid JSON key, used by some documents.
+	ir.AnnotationJSONName: {on: "field"},
+	ir.AnnotationJSONFlags: {on: "enum", valid: func(v string, _ bool) (string, bool) {
+		values := []string{ir.FlagsInt, ir.FlagsNames}
+		return quoteChoices(values), slices.Contains(values, v)
+	}},
+	ir.AnnotationAlias:    {on: "enum member", args: argValues},
+	ir.AnnotationFallback: {on: "enum member", args: argNone},
+	ir.AnnotationUnknown: {on: "enum", valid: func(v string, _ bool) (string, bool) {
+		values := []string{ir.UnknownReject, ir.UnknownFallback, ir.UnknownPreserve}
+		return quoteChoices(values), slices.Contains(values, v)
+	}},
+}
+
+func nonEmpty(expected string) func(string, bool) (string, bool) {
+	return func(v string, _ bool) (string, bool) {
+		return expected, strings.TrimSpace(v) != ""
+	}
+}
+
+// annotationTargets are the annotation namespaces of the built-in
+// annotations. Other namespaces are left to plugins, while names without a
+// namespace are reserved for the built-in annotations.
+var annotationTargets = map[string]bool{"go": true, "ts": true, "python": true, "json": true}
+
+// checkAnnotations checks the built-in annotations of every definition, field
+// and enum member of the namespace.
+func (a *analyzer) checkAnnotations(ns *ir.Namespace) {
+	for _, t := range ns.Types {
+		a.checkAnnotationList(t.Annotations, "type", t.Internal)
+		a.checkFieldAnnotations(ns, t.Fields)
+		a.checkMappedNames(ns, t.AllFields())
+	}
+	for _, e := range ns.Enums {
+		a.checkAnnotationList(e.Annotations, "enum", e.Internal)
+		for _, m := range e.Members {
+			a.checkAnnotationList(m.Annotations, "enum member", e.Internal)
+		}
+	}
+	for _, c := range ns.Consts {
+		a.checkAnnotationList(c.Annotations, "constant", c.Internal)
+		a.checkFieldAnnotations(ns, c.Type.Fields)
+		a.checkMappedNames(ns, c.Type.Fields)
+	}
+	for _, p := range ns.Patterns {
+		a.checkAnnotationList(p.Annotations, "pattern", p.Internal)
+	}
+}
+
+func (a *analyzer) checkFieldAnnotations(ns *ir.Namespace, fields []*ir.Field) {
+	for _, f := range fields {
+		a.checkAnnotationList(f.Annotations, "field", false)
+		if f.Type.Inline() {
+			a.checkFieldAnnotations(ns, f.Type.Fields)
+			a.checkMappedNames(ns, f.Type.Fields)
+		}
+	}
+}
+
+func (a *analyzer) checkAnnotationList(annotations ir.Annotations, kind string, internal bool) {
+	seen := map[string]bool{}
+	for _, an := range annotations {
+		spec, ok := annotationSpecs[an.Name]
+		if !ok {
+			target, _, qualified := strings.Cut(an.Name, ".")
+			if !qualified || annotationTargets[target] {
+				a.errorf(an.Pos, "unknown annotation \"@%s\"", an.Name)
+			}
+			continue
+		}
+
+		if seen[an.Name] {
+			a.errorf(an.Pos, "annotation \"@%s\" is set more than once", an.Name)
+			continue
+		}
+		seen[an.Name] = true
+
+		if spec.on != "" && kind != spec.on {
+			a.errorf(an.Pos, "annotation \"@%s\" can only be used on %ss, not on a %s", an.Name, spec.on, kind)
+			continue
+		}
+		switch spec.args {
+		case argNone:
+			if len(an.Args) > 0 {
+				a.errorf(an.Pos, "annotation \"@%s\" takes no arguments", an.Name)
+			}
+			continue
+		case argValues:
+			if len(an.Args) == 0 {
+				a.errorf(an.Pos, "annotation \"@%s\" expects one or more values", an.Name)
+			}
+			continue
+		}
+		if len(an.Args) != 1 || an.Args[0].String == nil {
+			a.errorf(an.Pos, "annotation \"@%s\" expects a single string argument", an.Name)
+			continue
+		}
+		if spec.valid == nil {
+			continue
+		}
+		if expected, ok := spec.valid(*an.Args[0].String, internal); !ok {
+			a.errorf(an.Args[0].Pos, "invalid value %q for annotation \"@%s\", expected %s", *an.Args[0].String, an.Name, expected)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+func TestAnnotationsValid(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			@go.name("TaskItem") @ts.name("TaskItem") @python.name("TaskItem")
+			type Task {
+				@go.name("ID") @json.name("task_id") @db.column("id", { primary: true })
+				id: string
+				@go.type("time.Duration") @ts.type("bigint")
+				timeout: int
+				@json.name("")
+				blank: string
+				meta: {
+					@json.name("created_by")
+					createdBy: string
+				}
+			}
+
+			@go.name("taskStatus")
+			internal enum TaskStatus {
+				@go.name("StatusPending")
+				PENDING
+			}
+
+			@plugin.anything(1, "two", [3])
+			const MaxRetries: int = 5
+		}
+	`
+
+	ns := resolve(t, input).Namespaces[0]
+	id := ns.Types[0].Fields[0]
+	assert.Equal(t, "task_id", ns.WireName(id))
+	assert.Equal(t, "timeout", ns.WireName(ns.Types[0].Fields[1]))
+	assert.Equal(t, "", ns.WireName(ns.Types[0].Fields[2]))
+
+	// Unknown annotations are kept for plugins.
+	column := id.Annotations.Get("db.column")
+	require.NotNil(t, column)
+	require.Len(t, column.Args, 2)
+	assert.NotNil(t, column.Args[1].Object)
+	assert.NotNil(t, ns.Consts[0].Annotations.Get("plugin.anything"))
+
+	name, ok := ns.Types[0].Annotations.String(ir.AnnotationGoName)
+	assert.True(t, ok)
+	assert.Equal(t, "TaskItem", name)
+}
+
+func TestAnnotationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name:     "unknown built-in",
+			input:    `@go.nmae("Task") type Task { id: string }`,
+			messages: []string{`unknown annotation "@go.nmae"`},
+		},
+		{
+			name:     "field annotation on a type",
+			input:    `@json.name("task") type Task { id: string }`,
+			messages: []string{`annotation "@json.name" can only be used on fields, not on a type`},
+		},
+		{
+			name:     "field annotation on an enum member",
+			input:    `enum Status { @ts.type("number") OPEN }`,
+			messages: []string{`annotation "@ts.type" can only be used on fields, not on a enum member`},
+		},
+		{
+			name:     "set more than once",
+			input:    `type Task { @go.name("ID") @go.name("Id") id: string }`,
+			messages: []string{`annotation "@go.name" is set more than once`},
+		},
+		{
+			name:     "missing argument",
+			input:    `type Task { @json.name id: string }`,
+			messages: []string{`annotation "@json.name" expects a single string argument`},
+		},
+		{
+			name:     "non-string argument",
+			input:    `type Task { @json.name(1) id: string }`,
+			messages: []string{`annotation "@json.name" expects a single string argument`},
+		},
+		{
+			name:     "unexported go name",
+			input:    `type Task { @go.name("id") id: string }`,
+			messages: []string{`invalid value "id" for annotation "@go.name", expected an exported Go identifier`},
+		},
+		{
+			name:     "reserved go name on an internal type",
+			input:    `@go.name("type") internal type Task { id: string }`,
+			messages: []string{`invalid value "type" for annotation "@go.name", expected a Go identifier`},
+		},
+		{
+			name:     "reserved ts name",
+			input:    `@ts.name("class") type Task { id: string }`,
+			messages: []string{`invalid value "class" for annotation "@ts.name", expected a TypeScript identifier`},
+		},
+		{
+			name:     "invalid python name",
+			input:    `const Max: int = 1 @python.name("max-value") const Min: int = 0`,
+			messages: []string{`invalid value "max-value" for annotation "@python.name", expected a Python identifier`},
+		},
+		{
+			name:     "unknown annotation without namespace",
+			input:    `@immutable type Task { id: string }`,
+			messages: []string{`unknown annotation "@immutable"`},
+		},
+		{
+			name:     "json name conflict",
+			input:    "type Task { taskId: string\n@json.name(\"taskId\") id: string }",
+			messages: []string{`wire name "taskId" of field "id" is already used by field "taskId"`},
+		},
+		{
+			name:     "json name conflict with a spread",
+			input:    "type Base { id: string }\ntype Task { ...Base\n@json.name(\"id\") taskId: string }",
+			messages: []string{`wire name "id" of field "taskId" is already used by field "id"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Tasks {\n"+tt.input+"\n}", Config{})
+			var messages []string
+			for _, d := range diags {
+				if d.Rule == "" {
+					messages = append(messages, d.Message)
+				}
+			}
+			require.Equal(t, tt.messages, messages)
+		})
+	}
+}
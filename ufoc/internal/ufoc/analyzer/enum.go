@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"math/bits"
+	"strings"
+
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// checkEnums checks the names, the wire values and the aliases of the enum
+// members, the bits of flags enums, and that the unknown-value policy of every
+// enum of the namespace agrees with its @fallback members.
+func (a *analyzer) checkEnums(ev *evaluator, ns *ir.Namespace) {
+	for _, e := range ns.Enums {
+		// Members declared twice or with invalid values are already reported
+		// and left out of the checks of their values.
+		skip := a.checkMemberNames(e)
+		for _, m := range e.Members {
+			if ev.members[m] == failed {
+				skip[m] = true
+			}
+		}
+		if e.Flags() {
+			a.checkFlags(e, skip)
+		} else {
+			a.checkEnumValues(e, skip)
+			if an := e.Annotations.Get(ir.AnnotationJSONFlags); an != nil {
+				a.errorf(an.Pos, "annotation \"@%s\" can only be used on flags enums", an.Name)
+			}
+		}
+		a.checkUnknownPolicy(e)
+	}
+}
+
+// checkMemberNames reports members declared more than once and returns them.
+func (a *analyzer) checkMemberNames(e *ir.Enum) map[*ir.EnumMember]bool {
+	seen := map[string]bool{}
+	dup := map[*ir.EnumMember]bool{}
+	for _, m := range e.Members {
+		if seen[m.Name] {
+			a.errorf(m.NamePos, "member %q is already declared in enum %q", m.Name, e.Name)
+			dup[m] = true
+			continue
+		}
+		seen[m.Name] = true
+	}
+	return dup
+}
+
+// checkFlags checks that the members of a flags enum have distinct single
+// bits and no annotations that only make sense for single values.
+func (a *analyzer) checkFlags(e *ir.Enum, skip map[*ir.EnumMember]bool) {
+	if len(e.Members) > ir.MaxFlags {
+		a.errorf(e.NamePos, "flags enum %q has %d members, the maximum is %d", e.Name, len(e.Members), ir.MaxFlags)
+		return
+	}
+
+	owners := map[uint64]*ir.EnumMember{}
+	for _, m := range e.Members {
+		for _, an := range m.Annotations {
+			if an.Name == ir.AnnotationAlias || an.Name == ir.AnnotationFallback {
+				a.errorf(an.Pos, "annotation \"@%s\" cannot be used in flags enum %q", an.Name, e.Name)
+			}
+		}
+
+		if skip[m] {
+			continue
+		}
+		bit := e.FlagValue(m)
+		if m.Value != nil && !singleBit(bit) {
+			a.errorf(m.Value.Pos, "value of member %q of flags enum %q must be a single bit, e.g. 1, 2, 4 or 8, up to 2^%d", m.Name, e.Name, ir.MaxFlags-1)
+			continue
+		}
+		if prev, ok := owners[bit]; ok {
+			a.errorf(m.NamePos, "bit %d of member %q is already used by member %q of flags enum %q", bit, m.Name, prev.Name, e.Name)
+			continue
+		}
+		owners[bit] = m
+	}
+}
+
+func singleBit(v uint64) bool {
+	return v != 0 && v&(v-1) == 0 && bits.TrailingZeros64(v) < ir.MaxFlags
+}
+
+// checkEnumValues reports aliases that are not literals of the enum base type
+// and wire values, aliases included, used by more than one member.
+func (a *analyzer) checkEnumValues(e *ir.Enum, skip map[*ir.EnumMember]bool) {
+	owners := map[string]*ir.EnumMember{}
+	declare := func(value string, m *ir.EnumMember, an *ir.Annotation) {
+		prev, ok := owners[value]
+		switch {
+		case !ok:
+			owners[value] = m
+		case an != nil:
+			a.errorf(an.Pos, "alias %q of member %q is already used by member %q of enum %q", value, m.Name, prev.Name, e.Name)
+		case prev != m:
+			a.errorf(m.NamePos, "value %q of member %q is already used by member %q of enum %q", value, m.Name, prev.Name, e.Name)
+		}
+	}
+
+	for _, m := range e.Members {
+		if !skip[m] {
+			declare(m.WireValue(), m, nil)
+		}
+	}
+	for _, m := range e.Members {
+		for _, an := range m.Annotations {
+			if an.Name != ir.AnnotationAlias {
+				continue
+			}
+			for _, arg := range an.Args {
+				value, ok := baseLiteral(e, arg)
+				if !ok {
+					a.errorf(arg.Pos, "alias of member %q must be %s literal", m.Name, baseKind(e))
+					continue
+				}
+				declare(value, m, an)
+			}
+		}
+	}
+}
+
+// validBase reports whether the base type of e is omitted or one of the
+// enum base types. Invalid base types are reported while resolving.
+func validBase(e *ir.Enum) bool {
+	switch e.BaseType {
+	case "", string(ir.String), string(ir.Int), ir.FlagsBase:
+		return true
+	}
+	return false
+}
+
+// baseLiteral returns the literal v as a wire value of e, reporting whether it
+// is a literal of the base type of e: an integer for int and flags enums and
+// a string otherwise.
+func baseLiteral(e *ir.Enum, v *ir.Value) (string, bool) {
+	if e.BaseType == string(ir.Int) || e.Flags() {
+		if v.Number == nil || strings.Contains(*v.Number, ".") {
+			return "", false
+		}
+		return *v.Number, true
+	}
+	if v.String == nil {
+		return "", false
+	}
+	return *v.String, true
+}
+
+func baseKind(e *ir.Enum) string {
+	if e.BaseType == string(ir.Int) || e.Flags() {
+		return "an integer"
+	}
+	return "a string"
+}
+
+// checkUnknownPolicy reports enums with more than one @fallback member, and
+// @unknown policies that contradict the presence of a @fallback member.
+func (a *analyzer) checkUnknownPolicy(e *ir.Enum) {
+	var fallback *ir.EnumMember
+	for _, m := range e.Members {
+		if !m.Fallback() {
+			continue
+		}
+		if fallback != nil {
+			a.errorf(m.NamePos, "enum %q already has the @fallback member %q", e.Name, fallback.Name)
+			continue
+		}
+		fallback = m
+	}
+
+	policy := e.Annotations.Get(ir.AnnotationUnknown)
+	if policy == nil {
+		return
+	}
+	value, _ := e.Annotations.String(ir.AnnotationUnknown)
+	switch {
+	case value == ir.UnknownFallback && fallback == nil:
+		a.errorf(policy.Pos, "enum %q has the %q unknown-value policy but no @fallback member", e.Name, value)
+	case (value == ir.UnknownReject || value == ir.UnknownPreserve) && fallback != nil:
+		a.errorf(fallback.NamePos, "@fallback member %q conflicts with the %q unknown-value policy of enum %q", fallback.Name, value, e.Name)
+	}
+}
@@ -0,0 +1,269 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+func TestEnumsValid(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum TaskStatus {
+				""" Waiting to be picked up. """
+				PENDING
+				@alias("in_progress", "started")
+				RUNNING = "running"
+				@fallback
+				UNKNOWN
+			}
+
+			@unknown("preserve")
+			enum ErrorCode: int {
+				@alias(0)
+				UNKNOWN = 1
+				TIMEOUT = 100
+			}
+		}
+	`
+
+	ns := resolve(t, input).Namespaces[0]
+	status, code := ns.Enums[0], ns.Enums[1]
+	assert.Equal(t, "Waiting to be picked up.", status.Members[0].Doc.Text)
+	assert.Equal(t, ir.UnknownFallback, status.UnknownPolicy())
+	assert.Equal(t, ir.UnknownPreserve, code.UnknownPolicy())
+	assert.Equal(t, []string{"0"}, code.Members[0].Aliases())
+
+	m, err := status.Decode("in_progress")
+	require.NoError(t, err)
+	assert.Equal(t, "RUNNING", m.Name)
+}
+
+func TestEnumMemberExpressions(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			const Base: int = 100
+			const Prefix: string = "task."
+
+			enum ErrorCode: int {
+				UNKNOWN = -1
+				TIMEOUT = Base + 1
+				RETRY = ErrorCode.TIMEOUT * 2
+			}
+
+			enum Topic {
+				CREATED = Prefix + "created"
+			}
+		}
+	`
+
+	ns := resolve(t, input).Namespaces[0]
+	var values []string
+	for _, m := range append(ns.Enums[0].Members, ns.Enums[1].Members...) {
+		values = append(values, m.WireValue())
+	}
+	assert.Equal(t, []string{"-1", "101", "202", "task.created"}, values)
+}
+
+func TestEnumFlags(t *testing.T) {
+	input := `
+		version 1
+		namespace Auth {
+			@json.flags("names")
+			enum Permission: flags {
+				READ
+				WRITE
+				ADMIN = 64
+			}
+
+			type Grant {
+				permissions: Permission = [READ, WRITE]
+				owner: Permission = ADMIN
+			}
+		}
+	`
+
+	ns := resolve(t, input).Namespaces[0]
+	perm := ns.Enums[0]
+	assert.Equal(t, uint64(2), perm.FlagValue(perm.Members[1]))
+	assert.Equal(t, uint64(64), perm.FlagValue(perm.Members[2]))
+	assert.Equal(t, ir.FlagsNames, ns.FlagsEncoding(perm))
+
+	defaults := ns.Types[0].Fields[0].Default.Array.Items
+	assert.Same(t, perm.Members[1], defaults[1].Member)
+}
+
+func TestEnumErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name:     "duplicated value",
+			input:    `enum Status { ACTIVE = "active" ENABLED = "active" }`,
+			messages: []string{`value "active" of member "ENABLED" is already used by member "ACTIVE" of enum "Status"`},
+		},
+		{
+			name:     "alias of another member",
+			input:    `enum Status { ACTIVE @alias("ACTIVE", "on") ENABLED }`,
+			messages: []string{`alias "ACTIVE" of member "ENABLED" is already used by member "ACTIVE" of enum "Status"`},
+		},
+		{
+			name:     "repeated alias",
+			input:    `enum Status { @alias("on") ACTIVE @alias("on") ENABLED }`,
+			messages: []string{`alias "on" of member "ENABLED" is already used by member "ACTIVE" of enum "Status"`},
+		},
+		{
+			name:     "int member without a value",
+			input:    `enum Code: int { ONE = 1 TWO }`,
+			messages: []string{`member "TWO" of int enum "Code" must have an explicit integer value`},
+		},
+		{
+			name:     "string value in an int enum",
+			input:    `enum Code: int { ONE = "one" }`,
+			messages: []string{`value of member "ONE" of enum "Code" must be an integer literal`},
+		},
+		{
+			name:     "float value in an int enum",
+			input:    `enum Code: int { HALF = 1 / 2.0 }`,
+			messages: []string{`value of member "HALF" of enum "Code" must be an integer literal`},
+		},
+		{
+			name:     "number value in a string enum",
+			input:    `enum Status { ACTIVE = 1 }`,
+			messages: []string{`value of member "ACTIVE" of enum "Status" must be a string literal`},
+		},
+		{
+			name:     "string value in a flags enum",
+			input:    `enum Permission: flags { READ = "r" }`,
+			messages: []string{`value of member "READ" of enum "Permission" must be an integer literal`},
+		},
+		{
+			name:     "invalid member expression",
+			input:    `enum Code: int { ONE = Missing + 1 }`,
+			messages: []string{`invalid value for member "ONE" of enum "Code": unknown constant "Missing"`},
+		},
+		{
+			name:     "member referring to itself",
+			input:    `enum Code: int { ONE = Code.TWO TWO = Code.ONE }`,
+			messages: []string{`value of member "ONE" refers to itself`},
+		},
+		{
+			name:     "number alias of a string enum",
+			input:    `enum Status { @alias(1) ACTIVE }`,
+			messages: []string{`alias of member "ACTIVE" must be a string literal`},
+		},
+		{
+			name:     "string alias of an int enum",
+			input:    `enum Code: int { @alias("one") ONE = 1 }`,
+			messages: []string{`alias of member "ONE" must be an integer literal`},
+		},
+		{
+			name:     "alias without values",
+			input:    `enum Status { @alias ACTIVE }`,
+			messages: []string{`annotation "@alias" expects one or more values`},
+		},
+		{
+			name:     "fallback with arguments",
+			input:    `enum Status { @fallback("x") UNKNOWN }`,
+			messages: []string{`annotation "@fallback" takes no arguments`},
+		},
+		{
+			name:     "fallback on a field",
+			input:    `type Task { @fallback status: string }`,
+			messages: []string{`annotation "@fallback" can only be used on enum members, not on a field`},
+		},
+		{
+			name:     "unknown on a type",
+			input:    `@unknown("preserve") type Task { id: string }`,
+			messages: []string{`annotation "@unknown" can only be used on enums, not on a type`},
+		},
+		{
+			name:     "two fallback members",
+			input:    `enum Status { @fallback UNKNOWN @fallback OTHER }`,
+			messages: []string{`enum "Status" already has the @fallback member "UNKNOWN"`},
+		},
+		{
+			name:     "invalid policy",
+			input:    `@unknown("ignore") enum Status { ACTIVE }`,
+			messages: []string{`invalid value "ignore" for annotation "@unknown", expected "reject", "fallback" or "preserve"`},
+		},
+		{
+			name:     "flags value that is not a single bit",
+			input:    `enum Permission: flags { READ = 1 READ_WRITE = 3 }`,
+			messages: []string{`value of member "READ_WRITE" of flags enum "Permission" must be a single bit, e.g. 1, 2, 4 or 8, up to 2^62`},
+		},
+		{
+			name:     "flags value out of range",
+			input:    `enum Permission: flags { ALL = 9223372036854775808 }`,
+			messages: []string{`value of member "ALL" of flags enum "Permission" must be a single bit, e.g. 1, 2, 4 or 8, up to 2^62`},
+		},
+		{
+			name:     "repeated flags bit",
+			input:    `enum Permission: flags { READ = 4 VIEW = 4 }`,
+			messages: []string{`bit 4 of member "VIEW" is already used by member "READ" of flags enum "Permission"`},
+		},
+		{
+			name:     "duplicate member",
+			input:    `enum Status { ACTIVE ACTIVE }`,
+			messages: []string{`member "ACTIVE" is already declared in enum "Status"`},
+		},
+		{
+			name:     "duplicate flags member",
+			input:    `enum Permission: flags { READ READ }`,
+			messages: []string{`member "READ" is already declared in enum "Permission"`},
+		},
+		{
+			name:     "alias in flags enum",
+			input:    `enum Permission: flags { @alias("r") READ }`,
+			messages: []string{`annotation "@alias" cannot be used in flags enum "Permission"`},
+		},
+		{
+			name:     "json flags on a string enum",
+			input:    `@json.flags("names") enum Status { ACTIVE }`,
+			messages: []string{`annotation "@json.flags" can only be used on flags enums`},
+		},
+		{
+			name:     "invalid json flags",
+			input:    `@json.flags("bits") enum Permission: flags { READ }`,
+			messages: []string{`invalid value "bits" for annotation "@json.flags", expected "int" or "names"`},
+		},
+		{
+			name:     "flags default with an unknown member",
+			input:    "enum Permission: flags { READ }\ntype Grant { permissions: Permission = [READ, EXECUTE] }",
+			messages: []string{`invalid default value for field "permissions": [1]: "EXECUTE" is not a member of enum "Permission"`},
+		},
+		{
+			name:     "flags enum placeholder",
+			input:    "enum Permission: flags { READ }\npattern Topic = \"perms.{perm: Permission}\"",
+			messages: []string{`placeholder "perm" cannot have the flags enum type "Permission"`},
+		},
+		{
+			name:     "fallback policy without fallback member",
+			input:    `@unknown("fallback") enum Status { ACTIVE }`,
+			messages: []string{`enum "Status" has the "fallback" unknown-value policy but no @fallback member`},
+		},
+		{
+			name:     "fallback member with preserve policy",
+			input:    `@unknown("preserve") enum Status { ACTIVE @fallback UNKNOWN }`,
+			messages: []string{`@fallback member "UNKNOWN" conflicts with the "preserve" unknown-value policy of enum "Status"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, d := range analyze(t, "version 1\nnamespace Tasks {\n"+tt.input+"\n}", Config{}) {
+				if d.Rule == "" {
+					messages = append(messages, d.Message)
+				}
+			}
+			assert.Equal(t, tt.messages, messages)
+		})
+	}
+}
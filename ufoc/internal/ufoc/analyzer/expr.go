@@ -0,0 +1,462 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// errReported is returned for values whose problem has already been reported,
+// like references to constants with invalid values.
+var errReported = &valueError{}
+
+type evalState int
+
+const (
+	unevaluated evalState = iota
+	evaluating
+	evaluated
+	failed
+)
+
+// evaluator folds the constant expressions and references of the schema into
+// literal values. References are resolved in sc, the scope of the namespace
+// of the value being folded.
+type evaluator struct {
+	a      *analyzer
+	sc     *scope
+	owners map[*ir.Const]*scope
+	state  map[*ir.Const]evalState
+	stack  []*ir.Const
+	// enums maps the enum members to their enum, and enumOwners the enums to
+	// the scope of their namespace.
+	enums      map[*ir.EnumMember]*ir.Enum
+	enumOwners map[*ir.Enum]*scope
+	members    map[*ir.EnumMember]evalState
+}
+
+func newEvaluator(a *analyzer, scopes map[*ir.Namespace]*scope) *evaluator {
+	e := &evaluator{
+		a:          a,
+		owners:     map[*ir.Const]*scope{},
+		state:      map[*ir.Const]evalState{},
+		enums:      map[*ir.EnumMember]*ir.Enum{},
+		enumOwners: map[*ir.Enum]*scope{},
+		members:    map[*ir.EnumMember]evalState{},
+	}
+	for ns, sc := range scopes {
+		for _, c := range ns.Consts {
+			e.owners[c] = sc
+		}
+		for _, en := range ns.Enums {
+			e.enumOwners[en] = sc
+			for _, m := range en.Members {
+				e.enums[m] = en
+			}
+		}
+	}
+	return e
+}
+
+// evalConst folds and checks the value of a constant, evaluating the
+// constants it references first. It reports whether the value is valid.
+func (e *evaluator) evalConst(c *ir.Const) bool {
+	switch e.state[c] {
+	case evaluated:
+		return true
+	case failed:
+		return false
+	case evaluating:
+		i := slices.Index(e.stack, c)
+		var names []string
+		for _, s := range e.stack[i:] {
+			names = append(names, s.Name)
+			e.state[s] = failed
+		}
+		names = append(names, c.Name)
+		e.a.errorf(c.NamePos, "constant %q refers to itself: %s", c.Name, strings.Join(names, " -> "))
+		return false
+	}
+
+	e.state[c] = evaluating
+	e.stack = append(e.stack, c)
+	sc := e.sc
+	e.sc = e.owners[c]
+	err := e.fold(c.Value, "")
+	if err == nil {
+		err = e.checkValue(c.Type, c.Value, "")
+	}
+	e.sc = sc
+	e.stack = e.stack[:len(e.stack)-1]
+
+	if e.state[c] == failed {
+		// The constant is part of a cycle, which is already reported.
+		return false
+	}
+	if err != nil {
+		if err != errReported {
+			e.a.errorf(err.Pos, "invalid value for constant %q: %s", c.Name, err)
+		}
+		e.state[c] = failed
+		return false
+	}
+	e.state[c] = evaluated
+	return true
+}
+
+// evalMember folds the value of an enum member and checks it against the base
+// type of its enum. It reports whether the value is valid.
+func (e *evaluator) evalMember(m *ir.EnumMember) bool {
+	switch e.members[m] {
+	case evaluated:
+		return true
+	case failed:
+		return false
+	case evaluating:
+		e.a.errorf(m.NamePos, "value of member %q refers to itself", m.Name)
+		e.members[m] = failed
+		return false
+	}
+
+	en := e.enums[m]
+	if m.Value == nil {
+		if en.BaseType == string(ir.Int) {
+			e.a.errorf(m.NamePos, "member %q of int enum %q must have an explicit integer value", m.Name, en.Name)
+			e.members[m] = failed
+			return false
+		}
+		e.members[m] = evaluated
+		return true
+	}
+
+	e.members[m] = evaluating
+	sc := e.sc
+	e.sc = e.enumOwners[en]
+	err := e.fold(m.Value, "")
+	e.sc = sc
+
+	if e.members[m] == failed {
+		// The member is part of a cycle, which is already reported.
+		return false
+	}
+	if err != nil {
+		if err != errReported {
+			e.a.errorf(err.Pos, "invalid value for member %q of enum %q: %s", m.Name, en.Name, err)
+		}
+		e.members[m] = failed
+		return false
+	}
+	if _, ok := baseLiteral(en, m.Value.Literal()); !ok && validBase(en) {
+		e.a.errorf(m.Value.Pos, "value of member %q of enum %q must be %s literal", m.Name, en.Name, baseKind(en))
+		e.members[m] = failed
+		return false
+	}
+	e.members[m] = evaluated
+	return true
+}
+
+// fold evaluates the expressions and references in v, storing their literal
+// results in the values.
+func (e *evaluator) fold(v *ir.Value, path string) *valueError {
+	fail := func(format string, args ...any) *valueError {
+		return &valueError{Pos: v.Pos, Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+
+	switch {
+	case v.Array != nil:
+		for i, item := range v.Array.Items {
+			if err := e.fold(item, itemPath(path, i)); err != nil {
+				return err
+			}
+		}
+	case v.Object != nil:
+		for _, entry := range v.Object.Entries {
+			if err := e.fold(entry.Value, fieldPath(path, entry.Key)); err != nil {
+				return err
+			}
+		}
+	case v.Ident != nil:
+		return e.resolveRef(v, fail)
+	case v.Unary != nil:
+		if err := e.fold(v.Unary.Operand, path); err != nil {
+			return err
+		}
+		result, err := negate(e.scalar(v.Unary.Operand))
+		if err != nil {
+			return fail("%s", err)
+		}
+		result.Pos = v.Pos
+		v.Folded = result
+	case v.Binary != nil:
+		if err := e.fold(v.Binary.Left, path); err != nil {
+			return err
+		}
+		if err := e.fold(v.Binary.Right, path); err != nil {
+			return err
+		}
+		result, err := binary(v.Binary.Op, e.scalar(v.Binary.Left), e.scalar(v.Binary.Right))
+		if err != nil {
+			return fail("%s", err)
+		}
+		result.Pos = v.Pos
+		v.Folded = result
+	}
+	return nil
+}
+
+// resolveRef resolves a reference to a constant or a qualified enum member,
+// both of which can be qualified by a namespace path. Unqualified names that
+// are not constants are left for checkValue, since they can only be resolved
+// against the expected enum type.
+func (e *evaluator) resolveRef(v *ir.Value, fail func(format string, args ...any) *valueError) *valueError {
+	name := *v.Ident
+	if c, owner := e.sc.lookupConst(name); c != nil {
+		e.a.checkInternal(e.sc, owner, v.Pos, "constant", name, c.Internal)
+		if !e.evalConst(c) {
+			return errReported
+		}
+		v.Const = c
+		v.Folded = c.Value.Literal()
+		return nil
+	}
+
+	i := strings.LastIndexByte(name, '.')
+	if i < 0 {
+		return nil
+	}
+	enumName, memberName := name[:i], name[i+1:]
+	if _, enum, owner := e.sc.lookupType(enumName); enum != nil {
+		e.a.checkInternal(e.sc, owner, v.Pos, "enum", enumName, enum.Internal)
+		for _, m := range enum.Members {
+			if m.Name == memberName {
+				v.Member = m
+				if !e.evalMember(m) {
+					return errReported
+				}
+				return nil
+			}
+		}
+	}
+	return fail("unknown enum member %q", name)
+}
+
+// scalar returns the literal of a folded value, with references to enum
+// members replaced by the number or string they stand for in expressions and
+// in values of primitive types.
+func (e *evaluator) scalar(v *ir.Value) *ir.Value {
+	lit := v.Literal()
+	if lit.Member == nil {
+		return lit
+	}
+	return e.memberLiteral(lit.Member)
+}
+
+// memberLiteral returns the wire value of an enum member as a literal: its
+// explicit value, its bit for flags enums or its name.
+func (e *evaluator) memberLiteral(m *ir.EnumMember) *ir.Value {
+	en := e.enums[m]
+	if en != nil && en.Flags() {
+		n := strconv.FormatUint(en.FlagValue(m), 10)
+		return &ir.Value{Pos: m.NamePos, Number: &n}
+	}
+	if m.Value != nil {
+		return m.Value.Literal()
+	}
+	name := m.Name
+	return &ir.Value{Pos: m.NamePos, String: &name}
+}
+
+func itemPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func fieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// number is a parsed number literal.
+type number struct {
+	isInt bool
+	i     int64
+	f     float64
+}
+
+func parseNumber(v *ir.Value) (number, error) {
+	if v.Number == nil {
+		if v.Ident != nil && v.Member == nil && !keywordLiteral(v) {
+			return number{}, fmt.Errorf("unknown constant %q", *v.Ident)
+		}
+		return number{}, fmt.Errorf("expected a number, got %s", kind(v))
+	}
+	if !strings.Contains(*v.Number, ".") {
+		i, err := strconv.ParseInt(*v.Number, 10, 64)
+		if err != nil {
+			return number{}, fmt.Errorf("%s overflows int", *v.Number)
+		}
+		return number{isInt: true, i: i, f: float64(i)}, nil
+	}
+	f, err := strconv.ParseFloat(*v.Number, 64)
+	if err != nil {
+		return number{}, fmt.Errorf("%s overflows float", *v.Number)
+	}
+	return number{f: f}, nil
+}
+
+func (n number) value() (*ir.Value, error) {
+	var s string
+	if n.isInt {
+		s = strconv.FormatInt(n.i, 10)
+	} else {
+		if math.IsInf(n.f, 0) || math.IsNaN(n.f) {
+			return nil, fmt.Errorf("float overflow")
+		}
+		s = strconv.FormatFloat(n.f, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+	}
+	return &ir.Value{Number: &s}, nil
+}
+
+// kind describes a literal in error messages.
+func kind(v *ir.Value) string {
+	switch {
+	case v.String != nil:
+		return "a string"
+	case v.Number != nil:
+		return "a number"
+	case v.Array != nil:
+		return "an array"
+	case v.Object != nil:
+		return "an object"
+	case v.Null():
+		return "null"
+	case keywordLiteral(v):
+		return "a boolean"
+	default:
+		return "an enum member"
+	}
+}
+
+// keywordLiteral reports whether the value is one of the true, false and null
+// literals, which are parsed as identifiers.
+func keywordLiteral(v *ir.Value) bool {
+	return v.Ident != nil && (*v.Ident == "true" || *v.Ident == "false" || *v.Ident == "null")
+}
+
+func negate(v *ir.Value) (*ir.Value, error) {
+	n, err := parseNumber(v)
+	if err != nil {
+		return nil, err
+	}
+	if n.isInt {
+		if n.i == math.MinInt64 {
+			return nil, fmt.Errorf("integer overflow")
+		}
+		n.i = -n.i
+	}
+	n.f = -n.f
+	return n.value()
+}
+
+func binary(op string, l, r *ir.Value) (*ir.Value, error) {
+	if op == "+" && l.String != nil && r.String != nil {
+		s := *l.String + *r.String
+		return &ir.Value{String: &s}, nil
+	}
+	if l.String != nil || r.String != nil {
+		if op != "+" {
+			return nil, fmt.Errorf("operator %s cannot be applied to strings", op)
+		}
+		other := r
+		if r.String != nil {
+			other = l
+		}
+		if other.Ident != nil && other.Member == nil && !keywordLiteral(other) {
+			return nil, fmt.Errorf("unknown constant %q", *other.Ident)
+		}
+		return nil, fmt.Errorf("cannot concatenate a string with %s", kind(other))
+	}
+
+	a, err := parseNumber(l)
+	if err != nil {
+		return nil, err
+	}
+	b, err := parseNumber(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.isInt && b.isInt {
+		i, err := intOp(op, a.i, b.i)
+		if err != nil {
+			return nil, err
+		}
+		return number{isInt: true, i: i}.value()
+	}
+
+	var f float64
+	switch op {
+	case "+":
+		f = a.f + b.f
+	case "-":
+		f = a.f - b.f
+	case "*":
+		f = a.f * b.f
+	case "/":
+		if b.f == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		f = a.f / b.f
+	case "%":
+		return nil, fmt.Errorf("operator %% requires integers")
+	}
+	return number{f: f}.value()
+}
+
+// intOp applies an arithmetic operator to two integers, detecting overflows.
+func intOp(op string, a, b int64) (int64, error) {
+	overflow := fmt.Errorf("integer overflow")
+	switch op {
+	case "+":
+		s := a + b
+		if (s > a) != (b > 0) {
+			return 0, overflow
+		}
+		return s, nil
+	case "-":
+		s := a - b
+		if (s < a) != (b > 0) {
+			return 0, overflow
+		}
+		return s, nil
+	case "*":
+		if a == 0 || b == 0 {
+			return 0, nil
+		}
+		p := a * b
+		if p/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+			return 0, overflow
+		}
+		return p, nil
+	case "/", "%":
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		if a == math.MinInt64 && b == -1 {
+			return 0, overflow
+		}
+		if op == "/" {
+			return a / b, nil
+		}
+		return a % b, nil
+	}
+	return 0, fmt.Errorf("unknown operator %s", op)
+}
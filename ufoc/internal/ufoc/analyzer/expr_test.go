@@ -0,0 +1,206 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExprFolding(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum TaskStatus {
+				PENDING
+				DONE
+			}
+
+			enum ErrorCode: int {
+				UNKNOWN = 1
+				TIMEOUT = 100
+			}
+
+			const MaxBackoffMs: int = BaseBackoffMs * 32
+			const BaseBackoffMs: int = 100 + 25
+			const Precedence: int = 2 + 3 * 4 - 10 / 5
+			const Grouped: int = (2 + 3) * -(4 - 6)
+			const LeftToRight: int = 10 - 4 - 3
+			const Remainder: int = 17 % 5
+			const Ratio: float = 1 / 4.0
+			const Whole: float = 2.5 * 2
+			const ErrorQueue: string = "tasks.failed"
+			const DeadLetterQueue: string = ErrorQueue + ".dlq"
+			const InitialStatus: TaskStatus = TaskStatus.PENDING
+			const FinalStatus: TaskStatus = DONE
+			const CopiedStatus: TaskStatus = InitialStatus
+			const Queues: string[] = [ErrorQueue, DeadLetterQueue + ".v2"]
+			const RetryCode: int = ErrorCode.TIMEOUT * 2 + ErrorCode.UNKNOWN
+			const TimeoutCode: int = ErrorCode.TIMEOUT
+			const DoneTopic: string = "tasks." + TaskStatus.DONE
+			const DoneName: string = FinalStatus
+
+			type Worker {
+				backoffMs: int = MaxBackoffMs / 2
+				status: TaskStatus = TaskStatus.DONE
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+
+	numbers := map[string]string{}
+	texts := map[string]string{}
+	for _, c := range ns.Consts {
+		lit := c.Value.Literal()
+		if lit.Number != nil {
+			numbers[c.Name] = *lit.Number
+		}
+		if lit.String != nil {
+			texts[c.Name] = *lit.String
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"MaxBackoffMs":  "4000",
+		"BaseBackoffMs": "125",
+		"Precedence":    "12",
+		"Grouped":       "10",
+		"LeftToRight":   "3",
+		"Remainder":     "2",
+		"Ratio":         "0.25",
+		"Whole":         "5.0",
+		"RetryCode":     "201",
+		"TimeoutCode":   "100",
+	}, numbers)
+	assert.Equal(t, map[string]string{
+		"ErrorQueue":      "tasks.failed",
+		"DeadLetterQueue": "tasks.failed.dlq",
+		"DoneTopic":       "tasks.DONE",
+		"DoneName":        "DONE",
+	}, texts)
+
+	members := ns.Enums[0].Members
+	assert.Same(t, members[0], ns.Consts[10].Value.Literal().Member)
+	assert.Same(t, members[1], ns.Consts[11].Value.Literal().Member)
+	assert.Same(t, ns.Consts[10], ns.Consts[12].Value.Const)
+	assert.Same(t, members[0], ns.Consts[12].Value.Literal().Member)
+
+	queues := ns.Consts[13].Value.Array.Items
+	assert.Equal(t, "tasks.failed", *queues[0].Literal().String)
+	assert.Equal(t, "tasks.failed.dlq.v2", *queues[1].Literal().String)
+
+	fields := ns.Types[0].Fields
+	assert.Equal(t, "2000", *fields[0].Default.Literal().Number)
+	assert.Same(t, members[1], fields[1].Default.Member)
+}
+
+func TestExprErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name:     "unknown constant",
+			input:    "const A: int = B * 2",
+			messages: []string{`invalid value for constant "A": unknown constant "B"`},
+		},
+		{
+			name:     "unknown enum member",
+			input:    "enum Status { ACTIVE }\nconst A: Status = Status.DELETED",
+			messages: []string{`invalid value for constant "A": unknown enum member "Status.DELETED"`},
+		},
+		{
+			name:     "member of another enum",
+			input:    "enum Status { ACTIVE }\nenum Color { RED }\nconst A: Status = Color.RED",
+			messages: []string{`invalid value for constant "A": "Color.RED" is not a member of enum "Status"`},
+		},
+		{
+			name:     "int member concatenation",
+			input:    "enum Code: int { A = 1 }\nconst A: string = \"code-\" + Code.A",
+			messages: []string{`invalid value for constant "A": cannot concatenate a string with a number`},
+		},
+		{
+			name:     "string member arithmetic",
+			input:    "enum Status { ACTIVE }\nconst A: int = Status.ACTIVE * 2",
+			messages: []string{`invalid value for constant "A": operator * cannot be applied to strings`},
+		},
+		{
+			name:     "self reference",
+			input:    "const A: int = A + 1",
+			messages: []string{`constant "A" refers to itself: A -> A`},
+		},
+		{
+			name:     "cycle",
+			input:    "const A: int = B + 1\nconst B: int = C * 2\nconst C: int = A",
+			messages: []string{`constant "A" refers to itself: A -> B -> C -> A`},
+		},
+		{
+			name:     "overflow",
+			input:    "const A: int = 9223372036854775807 + 1",
+			messages: []string{`invalid value for constant "A": integer overflow`},
+		},
+		{
+			name:     "multiplication overflow",
+			input:    "const A: int = 4294967296 * 4294967296",
+			messages: []string{`invalid value for constant "A": integer overflow`},
+		},
+		{
+			name:     "int32 overflow",
+			input:    "const Base: int32 = 65536\nconst A: int32 = Base * Base",
+			messages: []string{`invalid value for constant "A": 4294967296 overflows int32`},
+		},
+		{
+			name:     "division by zero",
+			input:    "const A: int = 1 / (2 - 2)",
+			messages: []string{`invalid value for constant "A": division by zero`},
+		},
+		{
+			name:     "float for int",
+			input:    "const A: int = 5 / 2.0",
+			messages: []string{`invalid value for constant "A": 2.5 is not an integer`},
+		},
+		{
+			name:     "string arithmetic",
+			input:    "const A: string = \"a\" * 2",
+			messages: []string{`invalid value for constant "A": operator * cannot be applied to strings`},
+		},
+		{
+			name:     "string and number",
+			input:    "const A: string = \"a\" + 2",
+			messages: []string{`invalid value for constant "A": cannot concatenate a string with a number`},
+		},
+		{
+			name:     "wrong reference type",
+			input:    "const A: int = 5\nconst B: string = A",
+			messages: []string{`invalid value for constant "B": expected a string`},
+		},
+		{
+			name:     "reference to invalid constant",
+			input:    "const A: int = \"a\"\nconst B: int = A + 1",
+			messages: []string{`invalid value for constant "A": expected a number`},
+		},
+		{
+			name:     "default with unknown constant",
+			input:    "type T { size: int = Size }",
+			messages: []string{`invalid default value for field "size": unknown constant "Size"`},
+		},
+		{
+			name:     "nested expression",
+			input:    "type R { max: int }\nconst A: R = { max: 1 % 0 }",
+			messages: []string{`invalid value for constant "A": max: division by zero`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Tasks {\n"+tt.input+"\n}", Config{})
+			var messages []string
+			for _, d := range diags {
+				messages = append(messages, d.Message)
+			}
+			require.Equal(t, tt.messages, messages)
+		})
+	}
+}
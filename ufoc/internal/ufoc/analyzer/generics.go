@@ -0,0 +1,233 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/uforg/ufocontract/internal/ufoc/casing"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// maxInstanceLabelLen bounds the size of generic instantiations, which stops
+// recursive generic types like type Nest<T> { next?: Nest<Nest<T>> } from
+// being instantiated forever.
+const maxInstanceLabelLen = 256
+
+// checkTypeParams checks the type parameters of a generic type.
+func (a *analyzer) checkTypeParams(sc *scope, t *ir.Type) {
+	seen := map[string]bool{}
+	for _, p := range t.TypeParams {
+		switch {
+		case seen[p.Name]:
+			a.errorf(p.Pos, "type parameter %q is already declared in type %q", p.Name, t.Name)
+		case sc.names[p.Name]:
+			a.errorf(p.Pos, "type parameter %q of type %q shadows a definition with the same name", p.Name, t.Name)
+		default:
+			if _, ok := ir.LookupPrimitive(p.Name); ok {
+				a.errorf(p.Pos, "type parameter %q of type %q shadows a primitive type", p.Name, t.Name)
+			}
+		}
+		seen[p.Name] = true
+	}
+}
+
+// resolveTypeArgs resolves the type arguments of a resolved reference and
+// checks that their number matches the type parameters of the referenced type.
+// References with concrete arguments are queued for instantiation.
+func (a *analyzer) resolveTypeArgs(sc *scope, ref *ir.TypeRef) {
+	for _, arg := range ref.Args {
+		if arg.Inline() {
+			a.errorf(arg.Pos, "inline types cannot be used as type arguments")
+			continue
+		}
+		a.resolveTypeRef(sc, arg)
+	}
+
+	want := 0
+	if ref.Type != nil {
+		want = len(ref.Type.TypeParams)
+	}
+
+	switch {
+	case want == 0 && len(ref.Args) > 0:
+		a.errorf(ref.Pos, "type %q does not take type arguments", ref.Name)
+	case want != len(ref.Args):
+		a.errorf(ref.Pos, "generic type %q expects %d type argument(s), got %d", ref.Name, want, len(ref.Args))
+	case want > 0 && concrete(ref):
+		a.pending = append(a.pending, ref)
+	}
+}
+
+// concrete reports whether a resolved reference and all of its type arguments
+// are free of type parameters and resolution errors.
+func concrete(ref *ir.TypeRef) bool {
+	if ref.TypeParam != nil || ref.Inline() {
+		return false
+	}
+	if ref.Primitive == "" && ref.Type == nil && ref.Enum == nil {
+		return false
+	}
+	for _, arg := range ref.Args {
+		if !concrete(arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// instantiate creates an instance for every queued generic reference, adding
+// the instances to the namespace.
+func (a *analyzer) instantiate(sc *scope, ns *ir.Namespace) {
+	instances := map[string]*ir.Instance{}
+	names := map[string]bool{}
+
+	for len(a.pending) > 0 {
+		ref := a.pending[0]
+		a.pending = a.pending[1:]
+
+		key := instanceKey(ref)
+		if inst, ok := instances[key]; ok {
+			ref.Instance = inst
+			continue
+		}
+		label := instanceLabel(ref)
+		if len(label) > maxInstanceLabelLen {
+			a.errorf(ref.Pos, "generic type %q is instantiated recursively", ref.Name)
+			continue
+		}
+
+		inst := &ir.Instance{Generic: ref.Type, Args: ref.Args}
+		name := instanceName(ref)
+		switch {
+		case sc.names[name]:
+			a.errorf(ref.Pos, "instantiation %s is named %q, which is already declared", label, name)
+		case names[name]:
+			a.errorf(ref.Pos, "instantiation %s is named %q, which is already the name of another instantiation", label, name)
+		}
+		names[name] = true
+		instances[key] = inst
+		ns.Instances = append(ns.Instances, inst)
+		ref.Instance = inst
+
+		subst := map[*ir.TypeParam]*ir.TypeRef{}
+		for i, p := range ref.Type.TypeParams {
+			subst[p] = ref.Args[i]
+		}
+		s := &substitution{analyzer: a, site: ref, params: subst}
+
+		generic := ref.Type
+		inst.Type = &ir.Type{
+			Pos:        generic.Pos,
+			NamePos:    generic.NamePos,
+			Doc:        generic.Doc,
+			Deprecated: generic.Deprecated,
+			Name:       name,
+			Fields:     s.fields(generic.Fields),
+		}
+		for _, spread := range generic.Spreads {
+			inst.Type.Spreads = append(inst.Type.Spreads, &ir.Spread{Pos: spread.Pos, Type: s.typeRef(spread.Type)})
+		}
+	}
+}
+
+// substitution replaces the type parameters of a generic type with the type
+// arguments of the reference that instantiates it.
+type substitution struct {
+	analyzer *analyzer
+	site     *ir.TypeRef
+	params   map[*ir.TypeParam]*ir.TypeRef
+}
+
+func (s *substitution) fields(fields []*ir.Field) []*ir.Field {
+	out := make([]*ir.Field, 0, len(fields))
+	for _, f := range fields {
+		copied := *f
+		copied.Type = s.typeRef(f.Type)
+		out = append(out, &copied)
+	}
+	return out
+}
+
+func (s *substitution) typeRef(ref *ir.TypeRef) *ir.TypeRef {
+	if arg, ok := s.params[ref.TypeParam]; ok && ref.TypeParam != nil {
+		out := *arg
+		if ref.Array {
+			if arg.Array {
+				s.analyzer.errorf(s.site.Pos, "type argument %s[] cannot be used for %s[], nested arrays are not supported", arg.Name, ref.Name)
+			}
+			out.Array = true
+		}
+		return &out
+	}
+
+	out := *ref
+	out.Instance = nil
+	out.Fields = s.fields(ref.Fields)
+	out.Args = nil
+	for _, arg := range ref.Args {
+		out.Args = append(out.Args, s.typeRef(arg))
+	}
+	if ref.Type != nil && ref.Type.Generic() && len(out.Args) == len(ref.Type.TypeParams) && concrete(&out) {
+		s.analyzer.pending = append(s.analyzer.pending, &out)
+	}
+	return &out
+}
+
+// instanceKey identifies the instantiation of a generic reference by the
+// resolved generic type and type arguments, since the same name can refer to
+// different types depending on the namespace it is written in. The array
+// suffix of the reference itself is not part of the key.
+func instanceKey(ref *ir.TypeRef) string {
+	var b strings.Builder
+	switch {
+	case ref.Type != nil:
+		fmt.Fprintf(&b, "%p", ref.Type)
+	case ref.Enum != nil:
+		fmt.Fprintf(&b, "%p", ref.Enum)
+	default:
+		b.WriteString(string(ref.Primitive))
+	}
+	writeArgs(&b, ref, instanceKey)
+	return b.String()
+}
+
+// instanceLabel returns a generic reference as written, e.g.
+// Page<Envelope<string,Task[]>>, for messages.
+func instanceLabel(ref *ir.TypeRef) string {
+	var b strings.Builder
+	b.WriteString(ref.Name)
+	writeArgs(&b, ref, instanceLabel)
+	return b.String()
+}
+
+func writeArgs(b *strings.Builder, ref *ir.TypeRef, format func(*ir.TypeRef) string) {
+	if len(ref.Args) == 0 {
+		return
+	}
+	b.WriteString("<")
+	for i, arg := range ref.Args {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(format(arg))
+		if arg.Array {
+			b.WriteString("[]")
+		}
+	}
+	b.WriteString(">")
+}
+
+// instanceName returns the name of the monomorphized type of a generic
+// reference: Page<Task> is PageTask and Page<Envelope<string, Task[]>> is
+// PageEnvelopeStringTaskList.
+func instanceName(ref *ir.TypeRef) string {
+	var b strings.Builder
+	b.WriteString(casing.Pascal(ref.Name))
+	for _, arg := range ref.Args {
+		b.WriteString(instanceName(arg))
+		if arg.Array {
+			b.WriteString("List")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,284 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+func TestGenericsInstantiate(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type Task {
+				id: uuid
+			}
+
+			type Page<T> {
+				items: T[]
+				next?: string
+			}
+
+			type Envelope<K, V> {
+				key: K
+				value: V
+			}
+
+			type TaskList {
+				first: Page<Task>
+				second: Page<Task>
+				pairs: Envelope<string, Page<Task>>[]
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+
+	page := ns.Types[1]
+	assert.True(t, page.Generic())
+	assert.Same(t, page.TypeParams[0], page.Fields[0].Type.TypeParam)
+
+	var names []string
+	for _, inst := range ns.Instances {
+		names = append(names, inst.Type.Name)
+	}
+	assert.Equal(t, []string{"PageTask", "EnvelopeStringPageTask"}, names)
+
+	list := ns.Types[3]
+	pageTask := ns.Instances[0]
+	assert.Same(t, pageTask, list.Fields[0].Type.Instance)
+	assert.Same(t, pageTask, list.Fields[1].Type.Instance)
+	assert.Same(t, page, pageTask.Generic)
+
+	items := pageTask.Type.Fields[0]
+	assert.Same(t, ns.Types[0], items.Type.Type)
+	assert.True(t, items.Type.Array)
+	assert.Nil(t, items.Type.TypeParam)
+
+	value := ns.Instances[1].Type.Fields[1]
+	assert.Same(t, pageTask, value.Type.Instance)
+}
+
+func TestGenericsSpread(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type Paged<T> {
+				items: T[]
+				total: int
+			}
+
+			type TaskPage {
+				...Paged<string>
+				cursor: string
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+	require.Len(t, ns.Instances, 1)
+
+	var names []string
+	var itemsType *ir.TypeRef
+	for _, f := range ns.Types[1].AllFields() {
+		names = append(names, f.Name)
+		if f.Name == "items" {
+			itemsType = f.Type
+		}
+	}
+	assert.Equal(t, []string{"items", "total", "cursor"}, names)
+	assert.Equal(t, ir.String, itemsType.Primitive)
+}
+
+func TestGenericsRecursive(t *testing.T) {
+	input := `
+		version 1
+		namespace Trees {
+			type Node<T> {
+				value: T
+				children: Node<T>[]
+			}
+
+			type Tree {
+				root: Node<int>
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+	require.Len(t, ns.Instances, 1)
+	inst := ns.Instances[0]
+	assert.Same(t, inst, inst.Type.Fields[1].Type.Instance)
+}
+
+func TestGenericsAcrossNamespaces(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type Task {
+				id: uuid
+			}
+
+			type List {
+				wrap: Other.Wrap<Task>
+				page: Other.Page<Task>
+			}
+		}
+		namespace Other {
+			type Page<T> {
+				other: T[]
+			}
+
+			type Wrap<T> {
+				p: Page<T>
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	tasks, other := schema.Namespaces[0], schema.Namespaces[1]
+	require.Len(t, tasks.Instances, 2)
+
+	wrap := tasks.Instances[0]
+	page := wrap.Type.Fields[0].Type.Instance
+	assert.Same(t, other.Types[0], page.Generic)
+	assert.Same(t, page, tasks.Types[1].Fields[1].Type.Instance)
+	assert.Equal(t, "other", page.Type.Fields[0].Name)
+}
+
+func TestGenericsSameNameAcrossNamespaces(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type Task {
+				id: uuid
+			}
+
+			type Page<T> {
+				items: T[]
+			}
+
+			type List {
+				page: Page<Task>
+				wrap: Other.Wrap<Task>
+			}
+		}
+		namespace Other {
+			type Page<T> {
+				other: T[]
+			}
+
+			type Wrap<T> {
+				p: Page<T>
+			}
+		}
+	`
+
+	var messages []string
+	for _, d := range analyze(t, input, Config{}) {
+		messages = append(messages, d.Message)
+	}
+	assert.Equal(t, []string{
+		`instantiation Page<Task> is named "PageTask", which is already the name of another instantiation`,
+	}, messages)
+}
+
+func TestGenericsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name:     "missing arguments",
+			input:    "type Page<T> { items: T[] }\ntype List { page: Page }",
+			messages: []string{`generic type "Page" expects 1 type argument(s), got 0`},
+		},
+		{
+			name:     "too many arguments",
+			input:    "type Page<T> { items: T[] }\ntype List { page: Page<string, int> }",
+			messages: []string{`generic type "Page" expects 1 type argument(s), got 2`},
+		},
+		{
+			name:     "arguments on a non-generic type",
+			input:    "type Task { id: string }\ntype List { task: Task<string> }",
+			messages: []string{`type "Task" does not take type arguments`},
+		},
+		{
+			name:     "arguments on a primitive",
+			input:    "type List { id: string<int> }",
+			messages: []string{`type "string" does not take type arguments`},
+		},
+		{
+			name:     "arguments on a type parameter",
+			input:    "type Page<T> { items: T<int> }",
+			messages: []string{`type "T" does not take type arguments`},
+		},
+		{
+			name:     "unknown argument",
+			input:    "type Page<T> { items: T[] }\ntype List { page: Page<Task> }",
+			messages: []string{`unknown type "Task"`},
+		},
+		{
+			name:     "inline argument",
+			input:    "type Page<T> { items: T[] }\ntype List { page: Page<{ id: string }> }",
+			messages: []string{`inline types cannot be used as type arguments`},
+		},
+		{
+			name:     "parameter outside its type",
+			input:    "type Page<T> { items: T[] }\ntype List { item: T }",
+			messages: []string{`unknown type "T"`},
+		},
+		{
+			name:     "duplicate parameter",
+			input:    "type Pair<T, T> { first: T }",
+			messages: []string{`type parameter "T" is already declared in type "Pair"`},
+		},
+		{
+			name:     "parameter shadows a type",
+			input:    "type Task { id: string }\ntype Page<Task> { items: Task[] }",
+			messages: []string{`type parameter "Task" of type "Page" shadows a definition with the same name`},
+		},
+		{
+			name:     "parameter shadows a primitive",
+			input:    "type Page<string> { items: string[] }",
+			messages: []string{`type parameter "string" of type "Page" shadows a primitive type`},
+		},
+		{
+			name:     "spread parameter",
+			input:    "type Wrap<T> { ...T }",
+			messages: []string{`cannot spread the type parameter "T"`},
+		},
+		{
+			name:     "nested arrays",
+			input:    "type Page<T> { items: T[] }\ntype List { page: Page<string[]> }",
+			messages: []string{`type argument string[] cannot be used for T[], nested arrays are not supported`},
+		},
+		{
+			name:     "instance name conflict",
+			input:    "type Page<T> { items: T[] }\ntype PageString { id: string }\ntype List { page: Page<string> }",
+			messages: []string{`instantiation Page<string> is named "PageString", which is already declared`},
+		},
+		{
+			name:     "infinite instantiation",
+			input:    "type Nest<T> { next?: Nest<Nest<T>> }\ntype Root { nest: Nest<int> }",
+			messages: []string{`generic type "Nest" is instantiated recursively`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Tasks {\n"+tt.input+"\n}", Config{})
+			var messages []string
+			for _, d := range diags {
+				messages = append(messages, d.Message)
+			}
+			require.Equal(t, tt.messages, messages)
+		})
+	}
+}
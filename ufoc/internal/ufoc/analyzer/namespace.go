@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+	"github.com/uforg/ufocontract/internal/ufoc/reserved"
+)
+
+// choiceOptions are the namespace options that accept one of a fixed set of
+// values.
+var choiceOptions = map[string][]string{
+	ir.OptionGoUUID:     {ir.GoUUIDString, ir.GoUUIDBytes},
+	ir.OptionGoSpread:   {ir.GoSpreadEmbed, ir.GoSpreadCopy},
+	ir.OptionWildcard:   {ir.WildcardNATS, ir.WildcardMQTT, ir.WildcardRegex, ir.WildcardRedis},
+	ir.OptionWireCase:   {ir.CasePreserve, ir.CaseCamel, ir.CaseSnake, ir.CaseKebab, ir.CasePascal},
+	ir.OptionTSCase:     {ir.CasePreserve, ir.CaseCamel, ir.CaseSnake},
+	ir.OptionPythonCase: {ir.CasePreserve, ir.CaseCamel, ir.CaseSnake},
+	ir.OptionJSONFlags:  {ir.FlagsInt, ir.FlagsNames},
+}
+
+var (
+	identRe        = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	goPackageRe    = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	tsModuleRe     = regexp.MustCompile(`^[A-Za-z0-9_.-]+(?:/[A-Za-z0-9_.-]+)*$`)
+	pythonModuleRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+)
+
+// checkNamespaces checks namespace names and options, including the ones of
+// nested namespaces. Quoted names only need to produce a valid identifier,
+// and the names used by the generators must be valid in their target
+// languages, whether they come from an option or not.
+func (a *analyzer) checkNamespaces(namespaces []*ir.Namespace) {
+	declared := map[string]*ir.Namespace{}
+
+	for _, ns := range namespaces {
+		if !identRe.MatchString(ns.Name) {
+			a.errorf(ns.NamePos, "cannot derive an identifier from namespace name %q", ns.DisplayName)
+			continue
+		}
+		if prev, ok := declared[ns.Name]; ok {
+			a.errorf(ns.NamePos, "namespace %q is already declared at %s", ns.Name, prev.NamePos)
+			continue
+		}
+		declared[ns.Name] = ns
+
+		a.checkNamespaceOptions(ns)
+		a.checkNamespaces(ns.Namespaces)
+	}
+}
+
+// checkFileOptions checks the file options, which set the choice options of
+// every namespace at once. The target names are specific to each namespace
+// and can only be set in one.
+func (a *analyzer) checkFileOptions(options []*ir.Option) {
+	a.checkOptions("file", options)
+}
+
+func (a *analyzer) checkNamespaceOptions(ns *ir.Namespace) {
+	a.checkOptions("namespace", ns.Options)
+
+	a.checkTargetName(ns, ir.OptionGoPackage, ns.GoPackage(), validGoPackage)
+	a.checkTargetName(ns, ir.OptionTSModule, ns.TSModule(), validTSModule)
+	a.checkTargetName(ns, ir.OptionPythonModule, ns.PythonModule(), validPythonModule)
+}
+
+// checkOptions checks the keys and the choice values of the options of a
+// namespace or a file.
+func (a *analyzer) checkOptions(kind string, options []*ir.Option) {
+	seen := map[string]bool{}
+	for _, o := range options {
+		switch o.Key {
+		case ir.OptionGoPackage, ir.OptionTSModule, ir.OptionPythonModule:
+			if kind != "namespace" {
+				a.errorf(o.Pos, "option %q can only be set in a namespace", o.Key)
+				continue
+			}
+		default:
+			values, ok := choiceOptions[o.Key]
+			if !ok {
+				a.errorf(o.Pos, "unknown %s option %q", kind, o.Key)
+				continue
+			}
+			if !slices.Contains(values, o.Value) {
+				a.errorf(o.Pos, "invalid value %q for %s option %q, expected %s", o.Value, kind, o.Key, quoteChoices(values))
+			}
+		}
+		if seen[o.Key] {
+			a.errorf(o.Pos, "%s option %q is already set", kind, o.Key)
+			continue
+		}
+		seen[o.Key] = true
+	}
+}
+
+func (a *analyzer) checkTargetName(ns *ir.Namespace, key, value string, valid func(string) bool) {
+	if valid(value) {
+		return
+	}
+
+	for _, o := range ns.Options {
+		if o.Key == key {
+			a.errorf(o.Pos, "invalid value %q for namespace option %q", value, key)
+			return
+		}
+	}
+	a.errorf(ns.NamePos, "derived value %q for namespace option %q is invalid, set the option explicitly", value, key)
+}
+
+func validGoPackage(s string) bool {
+	return goPackageRe.MatchString(s) && !reserved.Go.IsReserved(s)
+}
+
+func validTSModule(s string) bool {
+	if !tsModuleRe.MatchString(s) {
+		return false
+	}
+	for _, segment := range strings.Split(s, "/") {
+		if segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func validPythonModule(s string) bool {
+	if !pythonModuleRe.MatchString(s) {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if reserved.Python.IsReserved(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteChoices lists quoted values for error messages: "a", "b" or "c".
+func quoteChoices(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
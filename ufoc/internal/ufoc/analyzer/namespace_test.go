@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceValidOptions(t *testing.T) {
+	input := `
+		version 1
+		namespace "billing-v2" {
+			option go.package = "billing"
+			option ts.module = "billing/v2"
+			option python.module = "acme.billing_v2"
+			option go.uuid = "bytes"
+			option go.spread = "copy"
+			option pattern.wildcard = "mqtt"
+			option wire.case = "snake"
+			option ts.case = "camel"
+			option python.case = "snake"
+			option json.flags = "names"
+		}
+	`
+
+	assert.Empty(t, analyze(t, input, Config{}))
+}
+
+func TestNamespaceErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		message string
+	}{
+		{
+			name:    "underivable identifier",
+			input:   `namespace "--" {}`,
+			message: `cannot derive an identifier from namespace name "--"`,
+		},
+		{
+			name:    "identifier starting with a digit",
+			input:   `namespace "2024 billing" {}`,
+			message: `cannot derive an identifier from namespace name "2024 billing"`,
+		},
+		{
+			name:    "duplicated derived identifier",
+			input:   "namespace \"billing-v2\" {}\nnamespace BillingV2 {}",
+			message: `namespace "BillingV2" is already declared at`,
+		},
+		{
+			name:    "unknown option",
+			input:   `namespace Tasks { option rust.crate = "tasks" }`,
+			message: `unknown namespace option "rust.crate"`,
+		},
+		{
+			name:    "repeated option",
+			input:   "namespace Tasks {\noption go.package = \"a\"\noption go.package = \"b\"\n}",
+			message: `namespace option "go.package" is already set`,
+		},
+		{
+			name:    "invalid go package",
+			input:   `namespace Tasks { option go.package = "Tasks-V2" }`,
+			message: `invalid value "Tasks-V2" for namespace option "go.package"`,
+		},
+		{
+			name:    "go keyword package",
+			input:   `namespace Tasks { option go.package = "func" }`,
+			message: `invalid value "func" for namespace option "go.package"`,
+		},
+		{
+			name:    "derived go keyword package",
+			input:   `namespace Type {}`,
+			message: `derived value "type" for namespace option "go.package" is invalid, set the option explicitly`,
+		},
+		{
+			name:    "invalid ts module",
+			input:   `namespace Tasks { option ts.module = "../tasks" }`,
+			message: `invalid value "../tasks" for namespace option "ts.module"`,
+		},
+		{
+			name:    "absolute ts module",
+			input:   `namespace Tasks { option ts.module = "/tasks" }`,
+			message: `invalid value "/tasks" for namespace option "ts.module"`,
+		},
+		{
+			name:    "invalid python module",
+			input:   `namespace Tasks { option python.module = "acme.billing-v2" }`,
+			message: `invalid value "acme.billing-v2" for namespace option "python.module"`,
+		},
+		{
+			name:    "invalid go uuid",
+			input:   `namespace Tasks { option go.uuid = "array" }`,
+			message: `invalid value "array" for namespace option "go.uuid", expected "string" or "bytes"`,
+		},
+		{
+			name:    "invalid go spread",
+			input:   `namespace Tasks { option go.spread = "inline" }`,
+			message: `invalid value "inline" for namespace option "go.spread", expected "embed" or "copy"`,
+		},
+		{
+			name:    "invalid pattern wildcard",
+			input:   `namespace Tasks { option pattern.wildcard = "amqp" }`,
+			message: `invalid value "amqp" for namespace option "pattern.wildcard", expected "nats", "mqtt", "regex" or "redis"`,
+		},
+		{
+			name:    "invalid wire case",
+			input:   `namespace Tasks { option wire.case = "upper" }`,
+			message: `invalid value "upper" for namespace option "wire.case", expected "preserve", "camel", "snake", "kebab" or "pascal"`,
+		},
+		{
+			name:    "kebab ts case",
+			input:   `namespace Tasks { option ts.case = "kebab" }`,
+			message: `invalid value "kebab" for namespace option "ts.case", expected "preserve", "camel" or "snake"`,
+		},
+		{
+			name:    "target name file option",
+			input:   "option go.package = \"tasks\"\nnamespace Tasks {}",
+			message: `option "go.package" can only be set in a namespace`,
+		},
+		{
+			name:    "unknown file option",
+			input:   "option rust.crate = \"tasks\"\nnamespace Tasks {}",
+			message: `unknown file option "rust.crate"`,
+		},
+		{
+			name:    "invalid file option",
+			input:   "option python.case = \"pascal\"\nnamespace Tasks {}",
+			message: `invalid value "pascal" for file option "python.case"`,
+		},
+		{
+			name:    "repeated file option",
+			input:   "option wire.case = \"snake\"\noption wire.case = \"camel\"\nnamespace Tasks {}",
+			message: `file option "wire.case" is already set`,
+		},
+		{
+			name:    "nested namespace option",
+			input:   `namespace Billing { namespace Invoices { option go.package = "Invoices" } }`,
+			message: `invalid value "Invoices" for namespace option "go.package"`,
+		},
+		{
+			name:    "nested python keyword module",
+			input:   `namespace Billing { namespace "class" {} }`,
+			message: `derived value "billing.class" for namespace option "python.module" is invalid`,
+		},
+		{
+			name:    "python keyword module",
+			input:   `namespace Tasks { option python.module = "acme.class" }`,
+			message: `invalid value "acme.class" for namespace option "python.module"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\n"+tt.input, Config{})
+			require.Len(t, diags, 1)
+			assert.Contains(t, diags[0].Message, tt.message)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package analyzer
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+
+	"github.com/uforg/ufocontract/internal/ufoc/casing"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// convention is a naming convention enforced by a naming rule.
+type convention struct {
+	name    string
+	matches func(string) bool
+	convert func(string) string
+}
+
+var (
+	pascalCase         = convention{"PascalCase", casing.IsPascal, casing.Pascal}
+	camelCase          = convention{"camelCase", casing.IsCamel, casing.Camel}
+	screamingSnakeCase = convention{"UPPER_SNAKE_CASE", casing.IsScreamingSnake, casing.ScreamingSnake}
+)
+
+// checkNaming enforces the naming convention of every definition in the
+// namespace. Renames also update the references to the definitions in refs.
+func (a *analyzer) checkNaming(ns *ir.Namespace, refs refs) {
+	for _, t := range ns.Types {
+		a.checkName(RuleTypeNaming, "type", t.Name, pascalCase, diagnostic.Edit{Pos: t.NamePos, Old: t.Name}, refs.edits(t))
+		a.checkFieldNames(ns, t.Fields)
+	}
+	for _, e := range ns.Enums {
+		a.checkName(RuleEnumNaming, "enum", e.Name, pascalCase, diagnostic.Edit{Pos: e.NamePos, Old: e.Name}, refs.edits(e))
+		for _, m := range e.Members {
+			a.checkName(RuleEnumMemberNaming, "enum member", m.Name, screamingSnakeCase, diagnostic.Edit{Pos: m.NamePos, Old: m.Name}, refs.edits(m))
+		}
+	}
+	for _, c := range ns.Consts {
+		a.checkName(RuleConstNaming, "const", c.Name, pascalCase, diagnostic.Edit{Pos: c.NamePos, Old: c.Name}, refs.edits(c))
+		a.checkFieldNames(ns, c.Type.Fields)
+	}
+	for _, p := range ns.Patterns {
+		a.checkName(RulePatternNaming, "pattern", p.Name, pascalCase, diagnostic.Edit{Pos: p.NamePos, Old: p.Name}, nil)
+		for _, ph := range p.Placeholders() {
+			a.checkName(RulePlaceholderNaming, "placeholder", ph.Name, camelCase, diagnostic.Edit{Pos: ph.Pos, Old: ph.Name}, nil)
+		}
+	}
+}
+
+// checkFieldNames checks the names of fields declared in ns. Renames keep the
+// wire name of the field with a @json.name annotation when it would change.
+func (a *analyzer) checkFieldNames(ns *ir.Namespace, fields []*ir.Field) {
+	for _, f := range fields {
+		decl := diagnostic.Edit{Pos: f.NamePos, Old: f.Name}
+		_, named := f.Annotations.String(ir.AnnotationJSONName)
+		if wire := ns.WireName(f); !named && ir.ApplyCase(ns.WireCase(), camelCase.convert(f.Name)) != wire {
+			decl.New = fmt.Sprintf("@%s(%q) ", ir.AnnotationJSONName, wire)
+		}
+		a.checkName(RuleFieldNaming, "field", f.Name, camelCase, decl, nil)
+		a.checkFieldNames(ns, f.Type.Fields)
+	}
+}
+
+// checkName reports a name that does not follow conv, with a fix renaming the
+// declaration decl and the references refs. Text set in decl.New is kept
+// before the new name.
+func (a *analyzer) checkName(rule Rule, kind, name string, conv convention, decl diagnostic.Edit, refs []diagnostic.Edit) {
+	if conv.matches(name) {
+		return
+	}
+
+	msg := fmt.Sprintf("%s name %q should be %s", kind, name, conv.name)
+
+	var fix *diagnostic.Fix
+	if suggestion := conv.convert(name); suggestion != "" && conv.matches(suggestion) {
+		fix = &diagnostic.Fix{Message: fmt.Sprintf("rename to %q", suggestion)}
+		for _, e := range append([]diagnostic.Edit{decl}, refs...) {
+			e.New += suggestion
+			fix.Edits = append(fix.Edits, e)
+		}
+	}
+
+	a.report(rule, decl.Pos, msg, fix)
+}
+
+// refs holds the resolved references to the types, enums, enum members and
+// constants of a schema, as edits of the name they are written with.
+type refs map[any][]diagnostic.Edit
+
+// collectRefs collects the references of the type references and values of
+// the namespaces, which must be resolved and folded.
+func collectRefs(namespaces []*ir.Namespace) refs {
+	r := refs{}
+	enums := map[*ir.EnumMember]*ir.Enum{}
+	for _, ns := range namespaces {
+		for _, e := range ns.Enums {
+			for _, m := range e.Members {
+				enums[m] = e
+			}
+		}
+	}
+
+	for _, ns := range namespaces {
+		ir.WalkTypeRefs(ns, func(ref *ir.TypeRef) {
+			switch {
+			case ref.Type != nil:
+				r.add(ref.Type, ref.Pos, ref.Name, 0)
+			case ref.Enum != nil:
+				r.add(ref.Enum, ref.Pos, ref.Name, 0)
+			}
+		})
+
+		var value func(v *ir.Value)
+		value = func(v *ir.Value) {
+			switch {
+			case v == nil:
+			case v.Array != nil:
+				for _, item := range v.Array.Items {
+					value(item)
+				}
+			case v.Object != nil:
+				for _, entry := range v.Object.Entries {
+					value(entry.Value)
+				}
+			case v.Unary != nil:
+				value(v.Unary.Operand)
+			case v.Binary != nil:
+				value(v.Binary.Left)
+				value(v.Binary.Right)
+			case v.Const != nil:
+				r.add(v.Const, v.Pos, *v.Ident, 0)
+			case v.Member != nil:
+				r.add(v.Member, v.Pos, *v.Ident, 0)
+				if strings.Contains(*v.Ident, ".") {
+					r.add(enums[v.Member], v.Pos, *v.Ident, 1)
+				}
+			}
+		}
+		var fields func([]*ir.Field)
+		fields = func(fs []*ir.Field) {
+			for _, f := range fs {
+				value(f.Default)
+				fields(f.Type.Fields)
+			}
+		}
+		for _, t := range ns.Types {
+			fields(t.Fields)
+		}
+		for _, e := range ns.Enums {
+			for _, m := range e.Members {
+				value(m.Value)
+			}
+		}
+		for _, c := range ns.Consts {
+			value(c.Value)
+		}
+	}
+	return r
+}
+
+// add adds the reference to def written as the qualified name at pos. The
+// name of def is the segment of name at index back from its end.
+func (r refs) add(def any, pos lexer.Position, name string, back int) {
+	segments := strings.Split(name, ".")
+	i := len(segments) - 1 - back
+	skip := len(strings.Join(segments[:i], "."))
+	if i > 0 {
+		skip++
+	}
+	pos.Offset += skip
+	pos.Column += skip
+	r[def] = append(r[def], diagnostic.Edit{Pos: pos, Old: segments[i]})
+}
+
+// edits returns the edits of the references to def in source order.
+func (r refs) edits(def any) []diagnostic.Edit {
+	edits := r[def]
+	slices.SortFunc(edits, func(a, b diagnostic.Edit) int {
+		return cmp.Or(cmp.Compare(a.Pos.Filename, b.Pos.Filename), cmp.Compare(a.Pos.Offset, b.Pos.Offset))
+	})
+	return edits
+}
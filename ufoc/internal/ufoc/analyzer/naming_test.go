@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+)
+
+func TestNamingRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		rule       Rule
+		message    string
+		suggestion string
+		// edit is the new text of the declaration, the suggestion when empty.
+		edit string
+	}{
+		{"type", "type task_payload { id: string }", RuleTypeNaming, `type name "task_payload" should be PascalCase`, "TaskPayload", ""},
+		{"enum", "enum taskStatus { PENDING }", RuleEnumNaming, `enum name "taskStatus" should be PascalCase`, "TaskStatus", ""},
+		{"enum member", "enum Method { creditCard }", RuleEnumMemberNaming, `enum member name "creditCard" should be UPPER_SNAKE_CASE`, "CREDIT_CARD", ""},
+		{"const", "const MAX_RETRIES: int = 5", RuleConstNaming, `const name "MAX_RETRIES" should be PascalCase`, "MaxRetries", ""},
+		{"pattern", `pattern task_topic = "tasks"`, RulePatternNaming, `pattern name "task_topic" should be PascalCase`, "TaskTopic", ""},
+		{"field", "type Task { created_at: string }", RuleFieldNaming, `field name "created_at" should be camelCase`, "createdAt", `@json.name("created_at") createdAt`},
+		{"field with wire case", "option wire.case = \"snake\"\ntype Task { created_at: string }", RuleFieldNaming, `field name "created_at" should be camelCase`, "createdAt", ""},
+		{"field with json name", "type Task { @json.name(\"created\") created_at: string }", RuleFieldNaming, `field name "created_at" should be camelCase`, "createdAt", ""},
+		{"inline field", "type Task { meta: { Owner: string } }", RuleFieldNaming, `field name "Owner" should be camelCase`, "owner", `@json.name("Owner") owner`},
+		{"placeholder", `pattern TaskTopic = "tasks.{task_id: uuid}"`, RulePlaceholderNaming, `placeholder name "task_id" should be camelCase`, "taskId", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Tasks {\n"+tt.definition+"\n}", Config{})
+			require.Len(t, diags, 1)
+
+			d := diags[0]
+			assert.Equal(t, string(tt.rule), d.Rule)
+			assert.Equal(t, tt.message, d.Message)
+			require.NotNil(t, d.Fix)
+			assert.Equal(t, `rename to "`+tt.suggestion+`"`, d.Fix.Message)
+			require.NotEmpty(t, d.Fix.Edits)
+			edit := tt.edit
+			if edit == "" {
+				edit = tt.suggestion
+			}
+			assert.Equal(t, edit, d.Fix.Edits[0].New)
+			assert.Equal(t, d.Pos, d.Fix.Edits[0].Pos)
+		})
+	}
+}
+
+func TestNamingFixRenamesReferences(t *testing.T) {
+	input := "version 1\nnamespace Tasks {\nenum task_status { PENDING }\ntype Task {\nstatus: task_status\nhistory: task_status[]\n}\n}"
+
+	diags := analyze(t, input, Config{})
+	require.Len(t, diags, 1)
+	require.NotNil(t, diags[0].Fix)
+
+	edits := diags[0].Fix.Edits
+	require.Len(t, edits, 3)
+	assert.Equal(t, diags[0].Pos, edits[0].Pos)
+	assert.Equal(t, [2]int{5, 9}, [2]int{edits[1].Pos.Line, edits[1].Pos.Column})
+	assert.Equal(t, [2]int{6, 10}, [2]int{edits[2].Pos.Line, edits[2].Pos.Column})
+
+	for _, e := range edits {
+		assert.Equal(t, "task_status", e.Old)
+		assert.Equal(t, "TaskStatus", e.New)
+		assert.Equal(t, e.Old, input[e.Pos.Offset:e.Pos.Offset+len(e.Old)])
+	}
+}
+
+func TestNamingFixesCompile(t *testing.T) {
+	input := `version 1
+namespace Tasks {
+  enum task_status { open_now DONE }
+  enum Flag: flags { read_only }
+  const max_retries: int = 3
+  const Limit: int = max_retries * 2
+  pattern StatusTopic = "tasks.{status: task_status}"
+  type my_task {
+    status: task_status = task_status.open_now
+    other: task_status = open_now
+    flags: Flag = [read_only]
+    retries: int = max_retries + 1
+    created_at: string
+  }
+  namespace Inner {
+    type Sub { task: my_task }
+  }
+}
+namespace Other {
+  type Ref {
+    task: Tasks.my_task
+    status: Tasks.task_status = Tasks.task_status.open_now
+    retries: int = -Tasks.max_retries
+  }
+}
+`
+
+	diags := analyze(t, input, Config{})
+	require.Len(t, diags, 6)
+	var edits []diagnostic.Edit
+	for _, d := range diags {
+		require.NotNil(t, d.Fix, d.Message)
+		for _, e := range d.Fix.Edits {
+			assert.Equal(t, e.Old, input[e.Pos.Offset:e.Pos.Offset+len(e.Old)])
+			edits = append(edits, e)
+		}
+	}
+
+	slices.SortFunc(edits, func(a, b diagnostic.Edit) int { return b.Pos.Offset - a.Pos.Offset })
+	fixed := input
+	for _, e := range edits {
+		fixed = fixed[:e.Pos.Offset] + e.New + fixed[e.Pos.Offset+len(e.Old):]
+	}
+	assert.Empty(t, analyze(t, fixed, Config{}), fixed)
+}
+
+func TestNamingWithoutSuggestion(t *testing.T) {
+	diags := analyze(t, "version 1\nnamespace Tasks {\ntype _ { id: string }\n}", Config{})
+	require.Len(t, diags, 1)
+	assert.Nil(t, diags[0].Fix)
+}
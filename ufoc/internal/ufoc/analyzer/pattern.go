@@ -0,0 +1,184 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// placeholderPrimitives are the primitives that can be used as placeholder
+// types. They all have a textual form without whitespace or separators.
+var placeholderPrimitives = []ir.Primitive{ir.String, ir.Int, ir.Int32, ir.Bool, ir.UUID, ir.Date}
+
+var patternDialects = []string{ir.DialectNATS, ir.DialectMQTT, ir.DialectHTTP, ir.DialectRedis}
+
+// httpPathChars are the characters allowed in the literal text of HTTP
+// routes besides letters and digits: the unreserved and sub-delims characters
+// of RFC 3986, ":", "@" and the "/" separator.
+const httpPathChars = "-._~!$&'()*+,;=:@/"
+
+// checkPatterns resolves the placeholder types of every pattern in the
+// namespace and checks that the placeholders can be built and parsed back.
+func (a *analyzer) checkPatterns(sc *scope, ns *ir.Namespace) {
+	for _, p := range ns.Patterns {
+		a.checkDialect(p)
+		a.checkPattern(sc, p)
+	}
+}
+
+// checkDialect checks the literal text of a pattern against the syntax of its
+// dialect. Redis keys are binary safe and have no rules.
+func (a *analyzer) checkDialect(p *ir.Pattern) {
+	switch p.Dialect {
+	case "", ir.DialectRedis:
+	case ir.DialectNATS:
+		a.checkLiteralChars(p, "NATS subjects", func(r rune) bool {
+			return !unicode.IsSpace(r) && r != '*' && r != '>'
+		})
+		for _, tok := range splitPattern(p, '.') {
+			if tok.empty() {
+				a.errorf(tok.pos, "pattern %q contains an empty token, which is not allowed in NATS subjects", p.Name)
+				break
+			}
+		}
+	case ir.DialectMQTT:
+		a.checkLiteralChars(p, "MQTT topics", func(r rune) bool {
+			return r != '+' && r != '#' && r != 0
+		})
+	case ir.DialectHTTP:
+		if len(p.Segments) == 0 || !strings.HasPrefix(p.Segments[0].Literal, "/") {
+			a.errorf(p.NamePos, "HTTP pattern %q must start with \"/\"", p.Name)
+			return
+		}
+		a.checkLiteralChars(p, "HTTP paths", func(r rune) bool {
+			return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(httpPathChars, r))
+		})
+		// The first token is the empty one before the leading "/", and the
+		// last one is empty for routes with a trailing slash.
+		tokens := splitPattern(p, '/')
+		for _, tok := range tokens[1 : len(tokens)-1] {
+			if tok.empty() {
+				a.errorf(tok.pos, "pattern %q contains an empty path segment", p.Name)
+			}
+		}
+		for _, tok := range tokens[1:] {
+			if !tok.placeholder && (tok.text == "." || tok.text == "..") {
+				a.errorf(tok.pos, "pattern %q contains the path segment %q, which is not allowed in HTTP paths", p.Name, tok.text)
+			}
+		}
+	default:
+		a.errorf(p.DialectPos, "unknown dialect %q for pattern %q, expected %s", p.Dialect, p.Name, quoteChoices(patternDialects))
+	}
+}
+
+// checkLiteralChars reports the first character of the literal text of a
+// pattern that is not valid in the dialect.
+func (a *analyzer) checkLiteralChars(p *ir.Pattern, dialect string, valid func(rune) bool) {
+	for _, s := range p.Segments {
+		if i := strings.IndexFunc(s.Literal, func(r rune) bool { return !valid(r) }); i >= 0 {
+			r, _ := utf8.DecodeRuneInString(s.Literal[i:])
+			a.errorf(s.Pos, "pattern %q contains %q, which is not allowed in %s", p.Name, r, dialect)
+			return
+		}
+	}
+}
+
+// patternToken is a part of a pattern between two separators.
+type patternToken struct {
+	// pos is the position of the literal text the token starts in.
+	pos         lexer.Position
+	text        string
+	placeholder bool
+}
+
+func (t patternToken) empty() bool {
+	return t.text == "" && !t.placeholder
+}
+
+// splitPattern splits a pattern on sep. Placeholders are never empty, so
+// tokens that contain one are not empty either.
+func splitPattern(p *ir.Pattern, sep rune) []patternToken {
+	tokens := []patternToken{{pos: p.NamePos}}
+	for _, s := range p.Segments {
+		if s.Placeholder != nil {
+			tokens[len(tokens)-1].placeholder = true
+			continue
+		}
+		if tokens[len(tokens)-1].empty() {
+			tokens[len(tokens)-1].pos = s.Pos
+		}
+		for _, r := range s.Literal {
+			if r == sep {
+				tokens = append(tokens, patternToken{pos: s.Pos})
+				continue
+			}
+			tokens[len(tokens)-1].text += string(r)
+		}
+	}
+	return tokens
+}
+
+func (a *analyzer) checkPattern(sc *scope, p *ir.Pattern) {
+	seen := map[string]bool{}
+	var prev *ir.Placeholder
+	separated := false
+	for _, s := range p.Segments {
+		ph := s.Placeholder
+		if ph == nil {
+			separated = separated || strings.IndexFunc(s.Literal, isSeparator) >= 0
+			continue
+		}
+		if prev != nil && !separated {
+			// Placeholder values cannot contain separators, which is what
+			// makes the pattern values parsable.
+			a.errorf(ph.Pos, "placeholders %q and %q of pattern %q must be separated by a non-alphanumeric character", prev.Name, ph.Name, p.Name)
+		}
+		prev, separated = ph, false
+
+		if seen[ph.Name] && !ph.Reserved() {
+			a.errorf(ph.Pos, "placeholder %q appears more than once in pattern %q", ph.Name, p.Name)
+		}
+		seen[ph.Name] = true
+
+		if ph.Type == nil {
+			continue
+		}
+		if ph.Reserved() {
+			a.errorf(ph.Type.Pos, "reserved placeholder %q cannot have a type", ph.Name)
+			continue
+		}
+		a.checkPlaceholderType(sc, p, ph)
+	}
+}
+
+func (a *analyzer) checkPlaceholderType(sc *scope, p *ir.Pattern, ph *ir.Placeholder) {
+	ref := ph.Type
+	a.resolveTypeRef(sc, ref)
+	switch {
+	case ref.Enum != nil && ref.Enum.Flags():
+		a.errorf(ref.Pos, "placeholder %q cannot have the flags enum type %q", ph.Name, ref.Name)
+	case ref.Enum != nil && p.Dialect != ir.DialectHTTP:
+		// Values of HTTP routes are percent-encoded, so only the other
+		// dialects restrict the enum values.
+		illegal := p.IllegalChars()
+		for _, m := range ref.Enum.Members {
+			value := m.WireValue()
+			if i := strings.IndexAny(value, illegal); i >= 0 {
+				a.errorf(ph.Type.Pos, "value %q of enum member %s.%s contains %q, which is not allowed in pattern %q", value, ref.Enum.Name, m.Name, value[i:i+1], p.Name)
+			}
+		}
+	case ref.Primitive != "" && !slices.Contains(placeholderPrimitives, ref.Primitive),
+		ref.Type != nil:
+		a.errorf(ref.Pos, "placeholder %q cannot have type %q, expected string, int, int32, bool, uuid, date or an enum", ph.Name, ref.Name)
+	case (ref.Primitive == ir.UUID || ref.Primitive == ir.Date) && p.Dialect != ir.DialectHTTP && strings.ContainsRune(p.IllegalChars(), '-'):
+		a.errorf(ref.Pos, "placeholder %q of type %q cannot be used in pattern %q, which uses \"-\" as a separator", ph.Name, ref.Name, p.Name)
+	}
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+func TestPatternPlaceholderTypes(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum TaskStatus {
+				PENDING
+				IN_PROGRESS = "in-progress"
+			}
+
+			pattern TaskTopic = "{ns}.{taskId: uuid}.{shard: int}.{status: TaskStatus}.{name}"
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+	placeholders := ns.Patterns[0].Placeholders()
+	require.Len(t, placeholders, 4)
+	assert.Equal(t, ir.UUID, placeholders[0].Type.Primitive)
+	assert.Equal(t, ir.Int, placeholders[1].Type.Primitive)
+	assert.Same(t, ns.Enums[0], placeholders[2].Type.Enum)
+	assert.Nil(t, placeholders[3].Type)
+}
+
+func TestPatternErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name:     "unknown type",
+			input:    `pattern Topic = "tasks.{taskId: guid}"`,
+			messages: []string{`unknown type "guid"`},
+		},
+		{
+			name:     "unsupported primitive",
+			input:    `pattern Topic = "tasks.{at: datetime}"`,
+			messages: []string{`placeholder "at" cannot have type "datetime", expected string, int, int32, bool, uuid, date or an enum`},
+		},
+		{
+			name:     "custom type",
+			input:    "type Task { id: string }\npattern Topic = \"tasks.{task: Task}\"",
+			messages: []string{`placeholder "task" cannot have type "Task", expected string, int, int32, bool, uuid, date or an enum`},
+		},
+		{
+			name:     "uuid with dash separator",
+			input:    `pattern Topic = "tasks-{taskId: uuid}"`,
+			messages: []string{`placeholder "taskId" of type "uuid" cannot be used in pattern "Topic", which uses "-" as a separator`},
+		},
+		{
+			name:     "typed reserved placeholder",
+			input:    `pattern Topic = "{ns: string}.tasks"`,
+			messages: []string{`reserved placeholder "ns" cannot have a type`},
+		},
+		{
+			name:     "duplicate placeholder",
+			input:    `pattern Topic = "tasks.{taskId}.{taskId}"`,
+			messages: []string{`placeholder "taskId" appears more than once in pattern "Topic"`},
+		},
+		{
+			name:     "adjacent placeholders",
+			input:    `pattern Topic = "tasks.{taskId}{shard}"`,
+			messages: []string{`placeholders "taskId" and "shard" of pattern "Topic" must be separated by a non-alphanumeric character`},
+		},
+		{
+			name:     "placeholders separated by letters",
+			input:    `pattern Topic = "tasks.{taskId}x{shard}"`,
+			messages: []string{`placeholders "taskId" and "shard" of pattern "Topic" must be separated by a non-alphanumeric character`},
+		},
+		{
+			name:     "enum value with separator",
+			input:    "enum Region { EU_WEST = \"eu.west\" }\npattern Topic = \"tasks.{region: Region}\"",
+			messages: []string{`value "eu.west" of enum member Region.EU_WEST contains ".", which is not allowed in pattern "Topic"`},
+		},
+		{
+			name:     "enum value with wildcard",
+			input:    "enum Region { ANY = \"*\" }\npattern Topic = \"tasks/{region: Region}\"",
+			messages: []string{`value "*" of enum member Region.ANY contains "*", which is not allowed in pattern "Topic"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Tasks {\n"+tt.input+"\n}", Config{})
+			var messages []string
+			for _, d := range diags {
+				messages = append(messages, d.Message)
+			}
+			require.Equal(t, tt.messages, messages)
+		})
+	}
+}
+
+func TestPatternDialects(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum Region { EU_WEST = "eu.west" }
+
+			pattern TaskSubject: nats = "{ns}.tasks.{taskId}.updates"
+			pattern TaskTopic: mqtt = "tasks/{taskId}/updates/"
+			pattern TaskRoute: http = "/tasks/{taskId: uuid}/regions/{region: Region}:archive"
+			pattern TaskKey: redis = "tasks:{taskId}:lock #1"
+		}
+	`
+
+	ns := resolve(t, input).Namespaces[0]
+	assert.Equal(t, ir.DialectHTTP, ns.Patterns[2].Dialect)
+}
+
+func TestPatternDialectErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name:     "unknown dialect",
+			input:    `pattern Topic: amqp = "tasks"`,
+			messages: []string{`unknown dialect "amqp" for pattern "Topic", expected "nats", "mqtt", "http" or "redis"`},
+		},
+		{
+			name:     "nats empty token",
+			input:    `pattern Topic: nats = "tasks..{taskId}"`,
+			messages: []string{`pattern "Topic" contains an empty token, which is not allowed in NATS subjects`},
+		},
+		{
+			name:     "nats trailing separator",
+			input:    `pattern Topic: nats = "tasks.{taskId}."`,
+			messages: []string{`pattern "Topic" contains an empty token, which is not allowed in NATS subjects`},
+		},
+		{
+			name:     "nats wildcard",
+			input:    `pattern Topic: nats = "tasks.>"`,
+			messages: []string{`pattern "Topic" contains '>', which is not allowed in NATS subjects`},
+		},
+		{
+			name:     "nats whitespace",
+			input:    `pattern Topic: nats = "tasks. {taskId}"`,
+			messages: []string{`pattern "Topic" contains ' ', which is not allowed in NATS subjects`},
+		},
+		{
+			name:     "mqtt wildcard",
+			input:    `pattern Topic: mqtt = "tasks/{taskId}/#"`,
+			messages: []string{`pattern "Topic" contains '#', which is not allowed in MQTT topics`},
+		},
+		{
+			name:     "http without leading slash",
+			input:    `pattern Route: http = "tasks/{taskId}"`,
+			messages: []string{`HTTP pattern "Route" must start with "/"`},
+		},
+		{
+			name:     "http starting with a placeholder",
+			input:    `pattern Route: http = "{ns}/tasks"`,
+			messages: []string{`HTTP pattern "Route" must start with "/"`},
+		},
+		{
+			name:     "http query",
+			input:    `pattern Route: http = "/tasks?id={taskId}"`,
+			messages: []string{`pattern "Route" contains '?', which is not allowed in HTTP paths`},
+		},
+		{
+			name:     "http empty segment",
+			input:    `pattern Route: http = "/tasks//{taskId}"`,
+			messages: []string{`pattern "Route" contains an empty path segment`},
+		},
+		{
+			name:     "http dot segment",
+			input:    `pattern Route: http = "/tasks/../{taskId}"`,
+			messages: []string{`pattern "Route" contains the path segment "..", which is not allowed in HTTP paths`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Tasks {\n"+tt.input+"\n}", Config{})
+			var messages []string
+			for _, d := range diags {
+				messages = append(messages, d.Message)
+			}
+			require.Equal(t, tt.messages, messages)
+		})
+	}
+}
+
+func TestPatternDialectPosition(t *testing.T) {
+	input := "version 1\nnamespace Tasks {\npattern Topic: nats = \"tasks.{taskId}..x\"\n}"
+
+	diags := analyze(t, input, Config{})
+	require.Len(t, diags, 1)
+	assert.Equal(t, 3, diags[0].Pos.Line)
+	assert.Equal(t, 38, diags[0].Pos.Column)
+}
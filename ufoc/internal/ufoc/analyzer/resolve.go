@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// scope holds the definitions that can be referenced by name: the types,
+// enums, constants and nested namespaces of a namespace and, inside a generic
+// type, its type parameters. Names that are not declared in a namespace are
+// looked up in its enclosing namespaces, up to the root scope that holds the
+// top-level namespaces.
+type scope struct {
+	ns       *ir.Namespace
+	parent   *scope
+	children map[string]*scope
+	names    map[string]bool
+	types    map[string]*ir.Type
+	enums    map[string]*ir.Enum
+	consts   map[string]*ir.Const
+	params   map[string]*ir.TypeParam
+}
+
+// forType returns the scope used inside the body of t.
+func (sc *scope) forType(t *ir.Type) *scope {
+	if !t.Generic() {
+		return sc
+	}
+	inner := *sc
+	inner.params = map[string]*ir.TypeParam{}
+	for _, p := range t.TypeParams {
+		inner.params[p.Name] = p
+	}
+	return &inner
+}
+
+// lookup returns the scopes to look up a possibly qualified name in, nearest
+// first, and the name of the definition without its qualifier. Unqualified
+// names are looked up in the namespace and its enclosing namespaces, while
+// qualified ones (Billing.Invoices.Invoice) only in the namespace they name.
+func (sc *scope) lookup(name string) ([]*scope, string) {
+	i := strings.LastIndexByte(name, '.')
+	if i < 0 {
+		var scopes []*scope
+		for s := sc; s != nil; s = s.parent {
+			scopes = append(scopes, s)
+		}
+		return scopes, name
+	}
+	if target := sc.namespace(name[:i]); target != nil {
+		return []*scope{target}, name[i+1:]
+	}
+	return nil, name[i+1:]
+}
+
+// namespace returns the scope of the namespace a qualified path refers to. The
+// first part of the path is looked up like an unqualified name.
+func (sc *scope) namespace(path string) *scope {
+	parts := strings.Split(path, ".")
+	var target *scope
+	for s := sc; s != nil && target == nil; s = s.parent {
+		target = s.children[parts[0]]
+	}
+	for _, p := range parts[1:] {
+		if target == nil {
+			return nil
+		}
+		target = target.children[p]
+	}
+	return target
+}
+
+// lookupType returns the type or enum a possibly qualified name refers to and
+// the scope it is declared in.
+func (sc *scope) lookupType(name string) (*ir.Type, *ir.Enum, *scope) {
+	scopes, local := sc.lookup(name)
+	for _, s := range scopes {
+		if t, ok := s.types[local]; ok {
+			return t, nil, s
+		}
+		if e, ok := s.enums[local]; ok {
+			return nil, e, s
+		}
+	}
+	return nil, nil, nil
+}
+
+// lookupConst returns the constant a possibly qualified name refers to and
+// the scope it is declared in.
+func (sc *scope) lookupConst(name string) (*ir.Const, *scope) {
+	scopes, local := sc.lookup(name)
+	for _, s := range scopes {
+		if c, ok := s.consts[local]; ok {
+			return c, s
+		}
+	}
+	return nil, nil
+}
+
+// definition is any named definition of a namespace.
+type definition struct {
+	kind    string
+	name    string
+	namePos lexer.Position
+}
+
+// declareNamespaces declares the definitions of the namespaces and of their
+// nested namespaces, storing the scope of every namespace in scopes.
+func (a *analyzer) declareNamespaces(parent *scope, namespaces []*ir.Namespace, scopes map[*ir.Namespace]*scope) {
+	parent.children = map[string]*scope{}
+	for _, ns := range namespaces {
+		sc := a.declare(ns)
+		sc.parent = parent
+		if _, ok := parent.children[ns.Name]; !ok {
+			parent.children[ns.Name] = sc
+		}
+		scopes[ns] = sc
+		a.declareNamespaces(sc, ns.Namespaces, scopes)
+	}
+}
+
+// resolveNamespace resolves every type reference in the definitions of the
+// namespace, storing the result in the references.
+func (a *analyzer) resolveNamespace(sc *scope) {
+	ns := sc.ns
+	for _, t := range ns.Types {
+		a.checkTypeParams(sc, t)
+		a.resolveFields(sc.forType(t), t.Fields)
+		for _, s := range t.Spreads {
+			a.resolveSpread(sc.forType(t), s)
+		}
+	}
+	for _, c := range ns.Consts {
+		a.resolveTypeRef(sc, c.Type)
+	}
+}
+
+func (a *analyzer) declare(ns *ir.Namespace) *scope {
+	sc := &scope{
+		ns:     ns,
+		names:  map[string]bool{},
+		types:  map[string]*ir.Type{},
+		enums:  map[string]*ir.Enum{},
+		consts: map[string]*ir.Const{},
+	}
+
+	var defs []definition
+	for _, t := range ns.Types {
+		defs = append(defs, definition{"type", t.Name, t.NamePos})
+		sc.types[t.Name] = t
+	}
+	for _, e := range ns.Enums {
+		defs = append(defs, definition{"enum", e.Name, e.NamePos})
+		if _, ok := sc.types[e.Name]; !ok {
+			sc.enums[e.Name] = e
+		}
+		if !validBase(e) {
+			a.errorf(e.NamePos, "enum %q has base type %q, expected %q, %q or %q", e.Name, e.BaseType, ir.String, ir.Int, ir.FlagsBase)
+		}
+	}
+	for _, c := range ns.Consts {
+		defs = append(defs, definition{"const", c.Name, c.NamePos})
+		if _, ok := sc.consts[c.Name]; !ok {
+			sc.consts[c.Name] = c
+		}
+	}
+	for _, p := range ns.Patterns {
+		defs = append(defs, definition{"pattern", p.Name, p.NamePos})
+	}
+	for _, child := range ns.Namespaces {
+		defs = append(defs, definition{"namespace", child.Name, child.NamePos})
+	}
+
+	sort.SliceStable(defs, func(i, j int) bool {
+		return defs[i].namePos.Offset < defs[j].namePos.Offset
+	})
+
+	declared := map[string]definition{}
+	for _, d := range defs {
+		sc.names[d.name] = true
+		if _, ok := ir.LookupPrimitive(d.name); ok && (d.kind == "type" || d.kind == "enum") {
+			a.errorf(d.namePos, "%s name %q is reserved for a primitive type", d.kind, d.name)
+			continue
+		}
+		if prev, ok := declared[d.name]; ok {
+			// Namespaces declared twice are reported by checkNamespaces.
+			if prev.kind != "namespace" || d.kind != "namespace" {
+				a.errorf(d.namePos, "%q is already declared as a %s at %s", d.name, prev.kind, prev.namePos)
+			}
+			continue
+		}
+		declared[d.name] = d
+	}
+
+	return sc
+}
+
+func (a *analyzer) resolveFields(sc *scope, fields []*ir.Field) {
+	for _, f := range fields {
+		a.resolveTypeRef(sc, f.Type)
+	}
+}
+
+func (a *analyzer) resolveTypeRef(sc *scope, ref *ir.TypeRef) {
+	if ref.Inline() {
+		a.resolveFields(sc, ref.Fields)
+		return
+	}
+
+	if p, ok := sc.params[ref.Name]; ok {
+		ref.TypeParam = p
+	} else if p, ok := ir.LookupPrimitive(ref.Name); ok {
+		ref.Primitive = p
+	} else if t, e, owner := sc.lookupType(ref.Name); t != nil {
+		ref.Type = t
+		a.checkInternal(sc, owner, ref.Pos, "type", ref.Name, t.Internal)
+	} else if e != nil {
+		ref.Enum = e
+		a.checkInternal(sc, owner, ref.Pos, "enum", ref.Name, e.Internal)
+	} else {
+		a.errorf(ref.Pos, "unknown type %q", ref.Name)
+		return
+	}
+
+	a.resolveTypeArgs(sc, ref)
+}
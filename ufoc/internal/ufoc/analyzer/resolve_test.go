@@ -0,0 +1,209 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+func TestResolvePrimitives(t *testing.T) {
+	input := `
+		version 1
+		namespace Billing {
+			type Payment {
+				id: uuid
+				amount: decimal
+				receipt: bytes
+				dueDate: date
+				cutoff: time
+				paidAt: datetime
+				timeout: duration
+				attempts: int32
+				ratio: float32
+				count: int
+				weight: float
+				settled: bool
+				note: string
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	fields := schema.Namespaces[0].Types[0].Fields
+
+	expected := []ir.Primitive{
+		ir.UUID, ir.Decimal, ir.Bytes, ir.Date, ir.Time, ir.Datetime, ir.Duration,
+		ir.Int32, ir.Float32, ir.Int, ir.Float, ir.Bool, ir.String,
+	}
+	require.Len(t, fields, len(expected))
+	for i, p := range expected {
+		assert.Equal(t, p, fields[i].Type.Primitive, fields[i].Name)
+	}
+}
+
+func TestResolveCustomTypes(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum TaskStatus { PENDING }
+
+			type Task {
+				status: TaskStatus
+				history: { previous: TaskStatus[] }
+				parent?: Task
+			}
+
+			const DefaultStatus: TaskStatus = PENDING
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+	fields := ns.Types[0].Fields
+
+	assert.Same(t, ns.Enums[0], fields[0].Type.Enum)
+	assert.Same(t, ns.Enums[0], fields[1].Type.Fields[0].Type.Enum)
+	assert.Same(t, ns.Types[0], fields[2].Type.Type)
+	assert.Same(t, ns.Enums[0], ns.Consts[0].Type.Enum)
+}
+
+func TestResolveNestedNamespaces(t *testing.T) {
+	input := `
+		version 1
+		namespace Billing {
+			type Money { cents: int }
+			enum Currency { EUR USD }
+			const DefaultCurrency: Currency = EUR
+
+			namespace Invoices {
+				enum Status { OPEN PAID }
+
+				type Invoice {
+					total: Money
+					currency: Currency = Billing.DefaultCurrency
+					status: Billing.Invoices.Status = Status.OPEN
+				}
+			}
+
+			type Summary { last: Invoices.Invoice }
+		}
+		namespace Tasks {
+			type Page<T> { items: T[] }
+
+			type Task {
+				invoice?: Billing.Invoices.Invoice
+				status: Billing.Invoices.Status = Billing.Invoices.Status.PAID
+				page: Page<Billing.Money>
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	billing, tasks := schema.Namespaces[0], schema.Namespaces[1]
+	invoices := billing.Namespaces[0]
+	invoice := invoices.Types[0]
+
+	// Unqualified names are looked up in the enclosing namespaces too.
+	assert.Same(t, billing.Types[0], invoice.Fields[0].Type.Type)
+	assert.Same(t, billing.Enums[0], invoice.Fields[1].Type.Enum)
+	assert.Same(t, billing.Consts[0], invoice.Fields[1].Default.Const)
+	assert.Same(t, invoices.Enums[0], invoice.Fields[2].Type.Enum)
+	assert.Same(t, invoices.Enums[0].Members[0], invoice.Fields[2].Default.Member)
+	assert.Same(t, invoice, billing.Types[1].Fields[0].Type.Type)
+
+	task := tasks.Types[1]
+	assert.Same(t, invoice, task.Fields[0].Type.Type)
+	assert.Same(t, invoices.Enums[0].Members[1], task.Fields[1].Default.Member)
+	require.Len(t, tasks.Instances, 1)
+	assert.Equal(t, "PageBillingMoney", tasks.Instances[0].Type.Name)
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		message string
+	}{
+		{
+			name:    "unknown type",
+			input:   "type Task { owner: User }",
+			message: `unknown type "User"`,
+		},
+		{
+			name:    "unknown type in inline object",
+			input:   "type Task { meta: { owner: User[] } }",
+			message: `unknown type "User"`,
+		},
+		{
+			name:    "unknown const type",
+			input:   "const Limit: long = 5",
+			message: `unknown type "long"`,
+		},
+		{
+			name:    "const with custom type",
+			input:   "type Task { id: string }\nconst Default: Task = 5",
+			message: `invalid value for constant "Default": expected an object`,
+		},
+		{
+			name:    "duplicated definition",
+			input:   "type Task { id: string }\nenum Task { PENDING }",
+			message: `"Task" is already declared as a type at`,
+		},
+		{
+			name:    "const and pattern with the same name",
+			input:   "const Topic: string = \"a\"\npattern Topic = \"b\"",
+			message: `"Topic" is already declared as a const at`,
+		},
+		{
+			name:    "type shadowing a primitive",
+			input:   "type uuid { value: string }",
+			message: `type name "uuid" is reserved for a primitive type`,
+		},
+		{
+			name:    "unknown namespace",
+			input:   "type Task { owner: Users.User }",
+			message: `unknown type "Users.User"`,
+		},
+		{
+			name:    "unknown type in nested namespace",
+			input:   "namespace Users {}\ntype Task { owner: Users.User }",
+			message: `unknown type "Users.User"`,
+		},
+		{
+			name:    "type of a nested namespace without qualifier",
+			input:   "namespace Users { type User { id: string } }\ntype Task { owner: User }",
+			message: `unknown type "User"`,
+		},
+		{
+			name:    "namespace and type with the same name",
+			input:   "type Users { id: string }\nnamespace Users {}",
+			message: `"Users" is already declared as a type at`,
+		},
+		{
+			name:    "duplicated nested namespace",
+			input:   "namespace Users {}\nnamespace Users {}",
+			message: `namespace "Users" is already declared at`,
+		},
+		{
+			name:    "invalid enum base type",
+			input:   "enum Status: float { ONE = 1 }",
+			message: `enum "Status" has base type "float", expected "string", "int" or "flags"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Tasks {\n"+tt.input+"\n}", Config{})
+			var messages []string
+			for _, d := range diags {
+				if d.Rule == "" {
+					messages = append(messages, d.Message)
+				}
+			}
+			require.Len(t, messages, 1, diags)
+			assert.Contains(t, messages[0], tt.message)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// checkSpreads checks that the resolved spreads of every type in the
+// namespace do not form cycles and do not bring in fields whose names
+// conflict.
+func (a *analyzer) checkSpreads(ns *ir.Namespace) {
+	cyclic := a.checkSpreadCycles(ns)
+	for _, t := range ns.Types {
+		if !cyclic[t] {
+			a.checkSpreadConflicts(t)
+		}
+	}
+}
+
+// resolveSpread resolves the type of a spread and checks that it is a type.
+func (a *analyzer) resolveSpread(sc *scope, s *ir.Spread) {
+	ref := s.Type
+	if ref.Inline() {
+		a.errorf(ref.Pos, "only named types can be spread")
+		return
+	}
+
+	a.resolveTypeRef(sc, ref)
+	switch {
+	case ref.Array:
+		a.errorf(ref.Pos, "cannot spread the array type %s[]", ref.Name)
+		ref.Type = nil
+	case ref.Primitive != "" || ref.Enum != nil:
+		a.errorf(ref.Pos, "cannot spread %q, only types can be spread", ref.Name)
+	case ref.TypeParam != nil:
+		a.errorf(ref.Pos, "cannot spread the type parameter %q", ref.Name)
+	}
+}
+
+// checkSpreadCycles reports types that spread themselves, directly or through
+// other types, and returns the types that are part of a cycle.
+func (a *analyzer) checkSpreadCycles(ns *ir.Namespace) map[*ir.Type]bool {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := map[*ir.Type]int{}
+	cyclic := map[*ir.Type]bool{}
+	var path []*ir.Type
+
+	var visit func(t *ir.Type)
+	visit = func(t *ir.Type) {
+		state[t] = visiting
+		path = append(path, t)
+
+		for _, s := range t.Spreads {
+			next := s.Type.Type
+			if next == nil {
+				continue
+			}
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				start := len(path) - 1
+				for path[start] != next {
+					start--
+				}
+				names := make([]string, 0, len(path)-start+1)
+				for _, c := range path[start:] {
+					cyclic[c] = true
+					names = append(names, c.Name)
+				}
+				names = append(names, next.Name)
+				a.errorf(s.Pos, "type %q spreads itself: %s", next.Name, strings.Join(names, " -> "))
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[t] = done
+	}
+
+	for _, t := range ns.Types {
+		if state[t] == unvisited {
+			visit(t)
+		}
+	}
+
+	return cyclic
+}
+
+// checkSpreadConflicts reports fields of the flattened type whose names are
+// already taken by an earlier field or spread.
+func (a *analyzer) checkSpreadConflicts(t *ir.Type) {
+	if len(t.Spreads) == 0 {
+		return
+	}
+	// Spread types can be declared in other namespaces.
+	owners := map[*ir.Field]*ir.Type{}
+	fieldOwners(t, owners, map[*ir.Type]bool{})
+
+	type entry struct {
+		field *ir.Field
+		pos   lexer.Position
+	}
+
+	var entries []entry
+	for _, f := range t.Fields {
+		entries = append(entries, entry{f, f.NamePos})
+	}
+	for _, s := range t.Spreads {
+		if s.Type.Type == nil {
+			continue
+		}
+		for _, f := range s.Type.Type.AllFields() {
+			entries = append(entries, entry{f, s.Pos})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].pos.Offset < entries[j].pos.Offset
+	})
+
+	seen := map[string]*ir.Field{}
+	for _, e := range entries {
+		prev, ok := seen[e.field.Name]
+		if !ok {
+			seen[e.field.Name] = e.field
+			continue
+		}
+		if prev == e.field {
+			a.errorf(e.pos, "field %q of type %q is included more than once in type %q", e.field.Name, owners[e.field].Name, t.Name)
+			continue
+		}
+		a.errorf(e.pos, "field %q of type %q conflicts with field %q of type %q in type %q",
+			e.field.Name, owners[e.field].Name, prev.Name, owners[prev].Name, t.Name)
+	}
+}
+
+// fieldOwners maps the fields of t and of the types it spreads, directly or
+// not, to the type declaring them.
+func fieldOwners(t *ir.Type, owners map[*ir.Field]*ir.Type, seen map[*ir.Type]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+	for _, f := range t.Fields {
+		owners[f] = t
+	}
+	for _, s := range t.Spreads {
+		if s.Type.Type != nil {
+			fieldOwners(s.Type.Type, owners, seen)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpreadFlattensFields(t *testing.T) {
+	input := `
+		version 1
+		namespace Users {
+			type BaseEntity {
+				id: uuid
+				createdAt: datetime
+			}
+
+			type User {
+				...BaseEntity
+				email: string
+			}
+
+			type Admin {
+				...User
+				permissions: string[]
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	types := schema.Namespaces[0].Types
+	assert.Same(t, types[0], types[1].Spreads[0].Type.Type)
+
+	var names []string
+	for _, f := range types[2].AllFields() {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"id", "createdAt", "email", "permissions"}, names)
+}
+
+func TestSpreadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name:     "unknown type",
+			input:    "type User { ...Base }",
+			messages: []string{`unknown type "Base"`},
+		},
+		{
+			name:     "primitive",
+			input:    "type User { ...string }",
+			messages: []string{`cannot spread "string", only types can be spread`},
+		},
+		{
+			name:     "enum",
+			input:    "enum Status { ACTIVE }\ntype User { ...Status }",
+			messages: []string{`cannot spread "Status", only types can be spread`},
+		},
+		{
+			name:     "array",
+			input:    "type Base { id: string }\ntype User { ...Base[] }",
+			messages: []string{`cannot spread the array type Base[]`},
+		},
+		{
+			name:     "inline",
+			input:    "type User { ...{ id: string } }",
+			messages: []string{`only named types can be spread`},
+		},
+		{
+			name:     "self",
+			input:    "type User { ...User }",
+			messages: []string{`type "User" spreads itself: User -> User`},
+		},
+		{
+			name:     "cycle",
+			input:    "type A { ...B }\ntype B { ...C }\ntype C { ...A }",
+			messages: []string{`type "A" spreads itself: A -> B -> C -> A`},
+		},
+		{
+			name:     "own field conflict",
+			input:    "type Base { id: string }\ntype User { ...Base\nid: int }",
+			messages: []string{`field "id" of type "User" conflicts with field "id" of type "Base" in type "User"`},
+		},
+		{
+			name:     "spread conflict",
+			input:    "type Base { id: string }\ntype Other { id: string }\ntype User { ...Base\n...Other }",
+			messages: []string{`field "id" of type "Other" conflicts with field "id" of type "Base" in type "User"`},
+		},
+		{
+			name:     "nested spread conflict",
+			input:    "type Base { id: string }\ntype Mid { ...Base }\ntype User { id: string\n...Mid }",
+			messages: []string{`field "id" of type "Base" conflicts with field "id" of type "User" in type "User"`},
+		},
+		{
+			name:     "diamond",
+			input:    "type Base { id: string }\ntype A { ...Base }\ntype B { ...Base }\ntype User { ...A\n...B }",
+			messages: []string{`field "id" of type "Base" is included more than once in type "User"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Users {\n"+tt.input+"\n}", Config{})
+			var messages []string
+			for _, d := range diags {
+				messages = append(messages, d.Message)
+			}
+			require.Equal(t, tt.messages, messages)
+		})
+	}
+}
+
+func TestSpreadConflictAcrossNamespaces(t *testing.T) {
+	input := `
+		version 1
+		namespace Base {
+			type Audit { id: string }
+		}
+		namespace T {
+			type X {
+				...Base.Audit
+				id: string
+			}
+		}
+	`
+
+	var messages []string
+	for _, d := range analyze(t, input, Config{}) {
+		messages = append(messages, d.Message)
+	}
+	assert.Equal(t, []string{
+		`field "id" of type "X" conflicts with field "id" of type "Audit" in type "X"`,
+	}, messages)
+}
+
+func TestSpreadConflictPosition(t *testing.T) {
+	input := "version 1\nnamespace Users {\ntype Base { id: string }\ntype User {\nid: string\n  ...Base\n}\n}"
+
+	diags := analyze(t, input, Config{})
+	require.Len(t, diags, 1)
+	assert.Equal(t, 6, diags[0].Pos.Line)
+	assert.Equal(t, 3, diags[0].Pos.Column)
+}
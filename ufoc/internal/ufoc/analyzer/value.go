@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// valueError is a problem found in a literal value. Path locates the nested
+// value that has the problem, e.g. regions[1] or retry.maxAttempts, and is
+// empty for the top level value.
+type valueError struct {
+	Pos     lexer.Position
+	Path    string
+	Message string
+}
+
+func (e *valueError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// checkValues folds the enum member values, the constant values and the field
+// default values of the namespace of sc and checks them against their types.
+func (a *analyzer) checkValues(e *evaluator, sc *scope) {
+	e.sc = sc
+	for _, en := range sc.ns.Enums {
+		for _, m := range en.Members {
+			e.evalMember(m)
+		}
+	}
+	for _, c := range sc.ns.Consts {
+		e.evalConst(c)
+	}
+	for _, t := range sc.ns.Types {
+		e.checkDefaults(t.Fields)
+	}
+}
+
+// checkDefaults checks the default values of fields against their types,
+// including the fields of inline types.
+func (e *evaluator) checkDefaults(fields []*ir.Field) {
+	for _, f := range fields {
+		if f.Type.Inline() {
+			e.checkDefaults(f.Type.Fields)
+		}
+		if f.Default == nil {
+			continue
+		}
+		err := e.fold(f.Default, "")
+		if err == nil {
+			err = e.checkFieldValue(f, f.Default, "")
+		}
+		if err != nil && err != errReported {
+			e.a.errorf(err.Pos, "invalid default value for field %q: %s", f.Name, err)
+		}
+	}
+}
+
+// checkFieldValue checks a folded value of a field, which can be null when
+// the field is nullable.
+func (e *evaluator) checkFieldValue(f *ir.Field, v *ir.Value, path string) *valueError {
+	if f.Nullable && v.Literal().Null() {
+		return nil
+	}
+	return e.checkValue(f.Type, v, path)
+}
+
+// checkValue checks a folded value against a resolved type reference. It
+// stores the enum members and the fields referenced by the value in it, and
+// folds references to enum members in values of primitive types to their
+// wire values.
+func (e *evaluator) checkValue(ref *ir.TypeRef, v *ir.Value, path string) *valueError {
+	fail := func(format string, args ...any) *valueError {
+		return &valueError{Pos: v.Pos, Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+	orig := v
+	v = v.Literal()
+
+	switch {
+	case v.Null():
+		return fail("null is only allowed for nullable fields")
+	case ref.Array:
+		if v.Array == nil {
+			return fail("expected an array")
+		}
+		item := *ref
+		item.Array = false
+		for i, it := range v.Array.Items {
+			if err := e.checkValue(&item, it, itemPath(path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ref.Inline():
+		return e.checkObjectValue(ref.Fields, "", v, path, fail)
+	case ref.Instance != nil:
+		return e.checkObjectValue(ref.Instance.Type.AllFields(), ref.Instance.Type.Name, v, path, fail)
+	case ref.Type != nil:
+		if ref.Type.Generic() {
+			// The type arguments are invalid and already reported.
+			return nil
+		}
+		return e.checkObjectValue(ref.Type.AllFields(), ref.Type.Name, v, path, fail)
+	case ref.TypeParam != nil:
+		return fail("type parameter %q cannot have a value", ref.Name)
+	case ref.Enum != nil && ref.Enum.Flags() && v.Array != nil:
+		// A set of flags is written as the array of its members.
+		for i, it := range v.Array.Items {
+			if err := checkEnumValue(ref.Enum, it.Literal()); err != nil {
+				return &valueError{Pos: it.Pos, Path: itemPath(path, i), Message: err.Error()}
+			}
+		}
+	case ref.Enum != nil:
+		if err := checkEnumValue(ref.Enum, v); err != nil {
+			return fail("%s", err)
+		}
+	case ref.Primitive != "":
+		if v.Member != nil {
+			v = e.memberLiteral(v.Member)
+			orig.Folded = v
+		}
+		if err := checkPrimitiveValue(ref.Primitive, v); err != nil {
+			return fail("%s", err)
+		}
+	}
+	// Unresolved references are already reported.
+	return nil
+}
+
+func (e *evaluator) checkObjectValue(
+	fields []*ir.Field, typeName string, v *ir.Value, path string,
+	fail func(format string, args ...any) *valueError,
+) *valueError {
+	if v.Object == nil {
+		return fail("expected an object")
+	}
+
+	byName := map[string]*ir.Field{}
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+
+	set := map[string]bool{}
+	for _, entry := range v.Object.Entries {
+		f, ok := byName[entry.Key]
+		switch {
+		case !ok && typeName != "":
+			return &valueError{Pos: entry.Pos, Path: path, Message: fmt.Sprintf("unknown field %q in type %q", entry.Key, typeName)}
+		case !ok:
+			return &valueError{Pos: entry.Pos, Path: path, Message: fmt.Sprintf("unknown field %q", entry.Key)}
+		case set[entry.Key]:
+			return &valueError{Pos: entry.Pos, Path: path, Message: fmt.Sprintf("field %q is set more than once", entry.Key)}
+		}
+		set[entry.Key] = true
+		entry.Field = f
+		if err := e.checkFieldValue(f, entry.Value, fieldPath(path, entry.Key)); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range fields {
+		if !set[f.Name] && !f.Optional && f.Default == nil {
+			return fail("missing required field %q", f.Name)
+		}
+	}
+	return nil
+}
+
+func checkEnumValue(e *ir.Enum, v *ir.Value) error {
+	if v.Ident == nil {
+		return fmt.Errorf("expected a member of enum %q", e.Name)
+	}
+	if v.Member != nil {
+		if !slices.Contains(e.Members, v.Member) {
+			return fmt.Errorf("%q is not a member of enum %q", *v.Ident, e.Name)
+		}
+		return nil
+	}
+	for _, m := range e.Members {
+		if m.Name == *v.Ident {
+			v.Member = m
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a member of enum %q", *v.Ident, e.Name)
+}
+
+func checkPrimitiveValue(p ir.Primitive, v *ir.Value) error {
+	isBool := v.Ident != nil && (*v.Ident == "true" || *v.Ident == "false")
+	switch {
+	case v.Ident != nil && v.Member == nil && !isBool:
+		return fmt.Errorf("unknown constant %q", *v.Ident)
+	case p == ir.Bool:
+		if !isBool {
+			return fmt.Errorf("expected true or false")
+		}
+	case p.Numeric():
+		if v.Number == nil {
+			return fmt.Errorf("expected a number")
+		}
+		return p.CheckNumber(*v.Number)
+	default:
+		if v.String == nil {
+			return fmt.Errorf("expected a string")
+		}
+		return p.CheckString(*v.String)
+	}
+	return nil
+}
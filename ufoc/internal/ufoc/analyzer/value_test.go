@@ -0,0 +1,266 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueDefaults(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum TaskStatus {
+				PENDING
+				DONE
+			}
+
+			type ListOptions {
+				pageSize: int = 25
+				ratio: float32 = 0.5
+				status: TaskStatus = PENDING
+				archived: bool = false
+				since: date = "2024-05-01"
+				filter: {
+					query: string = "*"
+				}
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+	fields := ns.Types[0].Fields
+	assert.Same(t, ns.Enums[0].Members[0], fields[2].Default.Member)
+	assert.Nil(t, fields[0].Default.Member)
+	assert.Equal(t, "*", *fields[5].Type.Fields[0].Default.String)
+}
+
+func TestValueErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		message string
+	}{
+		{
+			name:    "string for int",
+			input:   `type T { size: int = "25" }`,
+			message: `invalid default value for field "size": expected a number`,
+		},
+		{
+			name:    "float for int",
+			input:   `type T { size: int = 2.5 }`,
+			message: `invalid default value for field "size": 2.5 is not an integer`,
+		},
+		{
+			name:    "int32 overflow",
+			input:   `type T { size: int32 = 3000000000 }`,
+			message: `invalid default value for field "size": 3000000000 overflows int32`,
+		},
+		{
+			name:    "number for string",
+			input:   `type T { name: string = 5 }`,
+			message: `invalid default value for field "name": expected a string`,
+		},
+		{
+			name:    "invalid bool",
+			input:   `type T { done: bool = 1 }`,
+			message: `invalid default value for field "done": expected true or false`,
+		},
+		{
+			name:    "invalid uuid",
+			input:   `type T { id: uuid = "abc" }`,
+			message: `invalid default value for field "id": "abc" is not a valid uuid`,
+		},
+		{
+			name:    "unknown enum member",
+			input:   "enum Status { ACTIVE }\ntype T { status: Status = DELETED }",
+			message: `invalid default value for field "status": "DELETED" is not a member of enum "Status"`,
+		},
+		{
+			name:    "string for enum",
+			input:   "enum Status { ACTIVE }\ntype T { status: Status = \"ACTIVE\" }",
+			message: `invalid default value for field "status": expected a member of enum "Status"`,
+		},
+		{
+			name:    "array",
+			input:   `type T { tags: string[] = "a" }`,
+			message: `invalid default value for field "tags": expected an array`,
+		},
+		{
+			name:    "custom type",
+			input:   "type A { id: string }\ntype T { a: A = 5 }",
+			message: `invalid default value for field "a": expected an object`,
+		},
+		{
+			name:    "array item",
+			input:   `const Regions: string[] = ["eu", 5]`,
+			message: `invalid value for constant "Regions": [1]: expected a string`,
+		},
+		{
+			name:    "nested field",
+			input:   "type Retry { maxAttempts: int }\ntype Policy { retry: Retry }\nconst Default: Policy = { retry: { maxAttempts: \"3\" } }",
+			message: `invalid value for constant "Default": retry.maxAttempts: expected a number`,
+		},
+		{
+			name:    "unknown field",
+			input:   "type Retry { maxAttempts: int }\nconst Default: Retry = { maxAttempts: 3, backoff: 5 }",
+			message: `invalid value for constant "Default": unknown field "backoff" in type "Retry"`,
+		},
+		{
+			name:    "unknown inline field",
+			input:   "const Default: { id: string } = { id: \"a\", name: \"b\" }",
+			message: `invalid value for constant "Default": unknown field "name"`,
+		},
+		{
+			name:    "duplicate field",
+			input:   "type Retry { maxAttempts: int }\nconst Default: Retry = { maxAttempts: 3, maxAttempts: 4 }",
+			message: `invalid value for constant "Default": field "maxAttempts" is set more than once`,
+		},
+		{
+			name:    "missing field",
+			input:   "type Retry { maxAttempts: int\nbackoffMs: int }\nconst Default: Retry = { maxAttempts: 3 }",
+			message: `invalid value for constant "Default": missing required field "backoffMs"`,
+		},
+		{
+			name:    "missing field in array item",
+			input:   "type Rule { id: string }\nconst Rules: Rule[] = [{ id: \"a\" }, {}]",
+			message: `invalid value for constant "Rules": [1]: missing required field "id"`,
+		},
+		{
+			name:    "object for array",
+			input:   "const Regions: string[] = {}",
+			message: `invalid value for constant "Regions": expected an array`,
+		},
+		{
+			name:    "type parameter",
+			input:   `type Box<T> { value: T = 5 }`,
+			message: `invalid default value for field "value": type parameter "T" cannot have a value`,
+		},
+		{
+			name:    "const",
+			input:   `const MaxRetries: int = "five"`,
+			message: `invalid value for constant "MaxRetries": expected a number`,
+		},
+		{
+			name:    "null for a field that is not nullable",
+			input:   `type T { note?: string = null }`,
+			message: `invalid default value for field "note": null is only allowed for nullable fields`,
+		},
+		{
+			name:    "null array item",
+			input:   `type T { tags: string[] | null = ["a", null] }`,
+			message: `invalid default value for field "tags": [1]: null is only allowed for nullable fields`,
+		},
+		{
+			name:    "missing nullable field",
+			input:   "type Patch { note: string | null }\nconst Empty: Patch = {}",
+			message: `invalid value for constant "Empty": missing required field "note"`,
+		},
+		{
+			name:    "null in an expression",
+			input:   `const Name: string = "a" + null`,
+			message: `invalid value for constant "Name": cannot concatenate a string with null`,
+		},
+		{
+			name:    "const enum member",
+			input:   "enum Status { ACTIVE }\nconst DefaultStatus: Status = DELETED",
+			message: `invalid value for constant "DefaultStatus": "DELETED" is not a member of enum "Status"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\nnamespace Tasks {\n"+tt.input+"\n}", Config{})
+			require.Len(t, diags, 1, diags)
+			assert.Equal(t, tt.message, diags[0].Message)
+		})
+	}
+}
+
+func TestValueNullable(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type TaskPatch {
+				title: string
+				note: string | null
+				dueDate?: date
+				assignee?: string | null = null
+			}
+
+			const ClearNote: TaskPatch = { title: "a", note: null }
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+	fields := ns.Types[0].Fields
+	assert.False(t, fields[0].Optional || fields[0].Nullable)
+	assert.True(t, fields[1].Nullable)
+	assert.False(t, fields[1].TriState())
+	assert.False(t, fields[2].Nullable)
+	assert.True(t, fields[3].TriState())
+	assert.True(t, fields[3].Default.Null())
+	assert.True(t, ns.Consts[0].Value.Object.Entry("note").Value.Null())
+}
+
+func TestValueStructured(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum Region {
+				EU
+				US
+			}
+
+			type Base {
+				name: string
+			}
+
+			type RetryPolicy {
+				...Base
+				maxAttempts: int
+				backoffMs: int = 100
+				jitter?: float
+				regions: Region[]
+			}
+
+			type Page<T> {
+				items: T[]
+			}
+
+			const AllowedRegions: string[] = ["eu", "us",]
+			const Empty: string[] = []
+			const DefaultRetryPolicy: RetryPolicy = {
+				name: "default",
+				maxAttempts: 3,
+				regions: [EU, US]
+			}
+			const FirstPage: Page<int> = { items: [1, 2, 3] }
+
+			type Worker {
+				retry: RetryPolicy = { name: "worker", maxAttempts: 5, regions: [] }
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	ns := schema.Namespaces[0]
+
+	regions := ns.Consts[0].Value.Array
+	require.Len(t, regions.Items, 2)
+	assert.Equal(t, "us", *regions.Items[1].String)
+	assert.Empty(t, ns.Consts[1].Value.Array.Items)
+
+	policy := ns.Consts[2].Value.Object
+	require.Len(t, policy.Entries, 3)
+	assert.Same(t, ns.Types[0].Fields[0], policy.Entry("name").Field)
+	assert.Same(t, ns.Types[1].Fields[0], policy.Entry("maxAttempts").Field)
+	assert.Same(t, ns.Enums[0].Members[1], policy.Entry("regions").Value.Array.Items[1].Member)
+	assert.Nil(t, policy.Entry("jitter"))
+
+	page := ns.Consts[3].Value.Object.Entry("items")
+	assert.Same(t, ns.Instances[0].Type.Fields[0], page.Field)
+}
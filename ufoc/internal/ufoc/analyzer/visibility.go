@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// checkInternal reports a reference from sc to an internal definition of the
+// namespace of owner when they do not belong to the same namespace tree.
+func (a *analyzer) checkInternal(sc, owner *scope, pos lexer.Position, kind, name string, internal bool) {
+	if internal && sc.ns.Root() != owner.ns.Root() {
+		a.errorf(pos, "%s %q is internal to namespace %q", kind, name, owner.ns.Root().Path())
+	}
+}
+
+// checkExposure reports public definitions of the namespace that use internal
+// types or enums, which would leak them into the generated public APIs.
+// Spreads are allowed, since their fields are flattened into the type.
+func (a *analyzer) checkExposure(ns *ir.Namespace) {
+	for _, t := range ns.Types {
+		if t.Internal {
+			continue
+		}
+		for _, f := range t.Fields {
+			a.checkExposedRef(f.Type, "type", t.Name)
+		}
+	}
+	for _, c := range ns.Consts {
+		if !c.Internal {
+			a.checkExposedRef(c.Type, "constant", c.Name)
+		}
+	}
+	for _, p := range ns.Patterns {
+		if p.Internal {
+			continue
+		}
+		for _, ph := range p.Placeholders() {
+			a.checkExposedRef(ph.Type, "pattern", p.Name)
+		}
+	}
+}
+
+func (a *analyzer) checkExposedRef(ref *ir.TypeRef, kind, name string) {
+	if ref == nil {
+		return
+	}
+	switch {
+	case ref.Type != nil && ref.Type.Internal:
+		a.errorf(ref.Pos, "public %s %q cannot use the internal type %q", kind, name, ref.Name)
+	case ref.Enum != nil && ref.Enum.Internal:
+		a.errorf(ref.Pos, "public %s %q cannot use the internal enum %q", kind, name, ref.Name)
+	}
+	for _, f := range ref.Fields {
+		a.checkExposedRef(f.Type, kind, name)
+	}
+	for _, arg := range ref.Args {
+		a.checkExposedRef(arg, kind, name)
+	}
+}
@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisibilityInternal(t *testing.T) {
+	input := `
+		version 1
+		namespace Billing {
+			internal type Audit { by: string }
+			internal enum Source { API IMPORT }
+			internal const MaxLines: int = 100
+
+			namespace Invoices {
+				type Invoice {
+					...Audit
+					lines: string[]
+				}
+
+				internal type Draft {
+					source: Source
+					limit: int = MaxLines
+				}
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	billing := schema.Namespaces[0]
+	assert.True(t, billing.Types[0].Internal)
+	assert.Same(t, billing.Types[0], billing.Namespaces[0].Types[0].Spreads[0].Type.Type)
+}
+
+func TestVisibilityErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name:     "internal type from another tree",
+			input:    "namespace Billing { internal type Audit { by: string } }\nnamespace Tasks { internal type Task { audit: Billing.Audit } }",
+			messages: []string{`type "Billing.Audit" is internal to namespace "Billing"`},
+		},
+		{
+			name:     "internal enum member from another tree",
+			input:    "namespace Billing { internal enum Source { API } }\nnamespace Tasks { const Default: string = Billing.Source.API }",
+			messages: []string{`enum "Billing.Source" is internal to namespace "Billing"`},
+		},
+		{
+			name:     "internal constant from another tree",
+			input:    "namespace Billing { internal const Max: int = 3 }\nnamespace Tasks { const Limit: int = Billing.Max }",
+			messages: []string{`constant "Billing.Max" is internal to namespace "Billing"`},
+		},
+		{
+			name:     "public type with an internal field type",
+			input:    "namespace Billing { internal type Audit { by: string }\ntype Invoice { audit: { last: Audit[] } } }",
+			messages: []string{`public type "Invoice" cannot use the internal type "Audit"`},
+		},
+		{
+			name:     "public constant with an internal enum",
+			input:    "namespace Billing { internal enum Source { API }\nconst Default: Source = API }",
+			messages: []string{`public constant "Default" cannot use the internal enum "Source"`},
+		},
+		{
+			name:     "public pattern with an internal enum",
+			input:    "namespace Billing { internal enum Source { API }\npattern Topic = \"billing.{source: Source}\" }",
+			messages: []string{`public pattern "Topic" cannot use the internal enum "Source"`},
+		},
+		{
+			name:     "internal type argument",
+			input:    "namespace Billing { internal type Audit { by: string }\ntype Page<T> { items: T[] }\ntype Log { page: Page<Audit> } }",
+			messages: []string{`public type "Log" cannot use the internal type "Audit"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := analyze(t, "version 1\n"+tt.input, Config{})
+			var messages []string
+			for _, d := range diags {
+				messages = append(messages, d.Message)
+			}
+			require.Equal(t, tt.messages, messages)
+		})
+	}
+}
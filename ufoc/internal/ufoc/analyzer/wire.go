@@ -0,0 +1,41 @@
+package analyzer
+
+import "github.com/uforg/ufocontract/internal/ufoc/ir"
+
+// fieldName is a name a field gets in encoded data or in generated code.
+type fieldName struct {
+	kind string
+	name func(ns *ir.Namespace, f *ir.Field) string
+}
+
+var fieldNames = []fieldName{
+	{"wire", (*ir.Namespace).WireName},
+	{"Go", (*ir.Namespace).GoName},
+	{"TypeScript", (*ir.Namespace).TSName},
+	{"Python", (*ir.Namespace).PythonName},
+}
+
+// checkMappedNames reports fields whose wire name or name in a target
+// language, set with an annotation or derived with the casing options, is
+// already used by another field of the same type.
+func (a *analyzer) checkMappedNames(ns *ir.Namespace, fields []*ir.Field) {
+	for _, fn := range fieldNames {
+		seen := map[string]*ir.Field{}
+		for _, f := range fields {
+			owner, ok := a.fieldOwners[f]
+			if !ok {
+				owner = ns
+			}
+			name := fn.name(owner, f)
+			prev, ok := seen[name]
+			if !ok {
+				seen[name] = f
+				continue
+			}
+			// Fields with the same name are reported as conflicts already.
+			if prev.Name != f.Name {
+				a.errorf(f.NamePos, "%s name %q of field %q is already used by field %q", fn.kind, name, f.Name, prev.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWireNames(t *testing.T) {
+	input := `
+		version 1
+		option wire.case = "snake"
+
+		namespace Common {
+			option wire.case = "kebab"
+			type Audit { createdAt: datetime }
+		}
+
+		namespace Tasks {
+			option python.case = "snake"
+			type Task {
+				...Common.Audit
+				taskId: uuid
+				@json.name("labels")
+				tagNames: string[]
+			}
+		}
+	`
+
+	schema := resolve(t, input)
+	common, tasks := schema.Namespaces[0], schema.Namespaces[1]
+	fields := tasks.Types[0].AllFields()
+	require.Len(t, fields, 3)
+
+	// Spread fields keep the wire case of their own namespace.
+	assert.Equal(t, "created-at", common.WireName(fields[0]))
+	assert.Equal(t, "task_id", tasks.WireName(fields[1]))
+	assert.Equal(t, "labels", tasks.WireName(fields[2]))
+	assert.Equal(t, "TaskId", tasks.GoName(fields[1]))
+	assert.Equal(t, "taskId", tasks.TSName(fields[1]))
+	assert.Equal(t, "tag_names", tasks.PythonName(fields[2]))
+}
+
+func TestWireNameErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		messages []string
+	}{
+		{
+			name: "derived wire names",
+			input: `namespace Tasks {
+				option wire.case = "snake"
+				type Task { user_id: string userId: string }
+			}`,
+			messages: []string{
+				`wire name "user_id" of field "userId" is already used by field "user_id"`,
+				`Go name "UserId" of field "userId" is already used by field "user_id"`,
+			},
+		},
+		{
+			name: "spread wire names",
+			input: `
+				namespace Common {
+					option wire.case = "kebab"
+					type Audit { createdAt: datetime }
+				}
+				namespace Tasks {
+					type Task { ...Common.Audit @json.name("created-at") createdOn: datetime }
+				}`,
+			messages: []string{`wire name "created-at" of field "createdOn" is already used by field "createdAt"`},
+		},
+		{
+			name: "derived go names",
+			input: `namespace Tasks {
+				type Task { taskId: string taskID: string }
+			}`,
+			messages: []string{`Go name "TaskId" of field "taskID" is already used by field "taskId"`},
+		},
+		{
+			name: "python names",
+			input: `namespace Tasks {
+				option python.case = "snake"
+				type Task { @python.name("task_id") id: string taskId: string }
+			}`,
+			messages: []string{`Python name "task_id" of field "taskId" is already used by field "id"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, d := range analyze(t, "version 1\n"+tt.input, Config{}) {
+				if d.Rule == "" {
+					messages = append(messages, d.Message)
+				}
+			}
+			assert.Equal(t, tt.messages, messages)
+		})
+	}
+}
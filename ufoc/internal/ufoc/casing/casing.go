@@ -0,0 +1,111 @@
+// Package casing splits identifiers into words and joins them back using the
+// casing conventions of the DSL and the generated code.
+package casing
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	pascalRe         = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+	camelRe          = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	screamingSnakeRe = regexp.MustCompile(`^[A-Z][A-Z0-9]*(?:_[A-Z0-9]+)*$`)
+)
+
+// IsPascal reports whether s is PascalCase, e.g. TaskStatus.
+func IsPascal(s string) bool { return pascalRe.MatchString(s) }
+
+// IsCamel reports whether s is camelCase, e.g. taskStatus.
+func IsCamel(s string) bool { return camelRe.MatchString(s) }
+
+// IsScreamingSnake reports whether s is UPPER_SNAKE_CASE, e.g. TASK_STATUS.
+func IsScreamingSnake(s string) bool { return screamingSnakeRe.MatchString(s) }
+
+// Words splits s into its words. Any character that is not a letter or a digit
+// separates words, as do case changes: "taskID", "task_id", "Task-Id" and
+// "TASK_ID" all split into "task" and "id" (keeping their original case).
+// Acronyms followed by a capitalized word are split before the last capital,
+// so "HTTPServer" becomes "HTTP" and "Server".
+func Words(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+
+	flush := func(end int) {
+		if start >= 0 && end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = -1
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush(i)
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		prev := runes[i-1]
+		switch {
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			// taskId, task2Id
+			flush(i)
+			start = i
+		case unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// HTTPServer
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(runes))
+
+	return words
+}
+
+// Pascal converts s to PascalCase.
+func Pascal(s string) string {
+	words := Words(s)
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, "")
+}
+
+// Camel converts s to camelCase.
+func Camel(s string) string {
+	words := Words(s)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+			continue
+		}
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, "")
+}
+
+// Snake converts s to snake_case.
+func Snake(s string) string {
+	return strings.ToLower(strings.Join(Words(s), "_"))
+}
+
+// ScreamingSnake converts s to UPPER_SNAKE_CASE.
+func ScreamingSnake(s string) string {
+	return strings.ToUpper(strings.Join(Words(s), "_"))
+}
+
+// Kebab converts s to kebab-case.
+func Kebab(s string) string {
+	return strings.ToLower(strings.Join(Words(s), "-"))
+}
+
+func capitalize(w string) string {
+	runes := []rune(strings.ToLower(w))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
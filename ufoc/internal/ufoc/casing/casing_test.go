@@ -0,0 +1,80 @@
+package casing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"taskId", []string{"task", "Id"}},
+		{"TaskStatus", []string{"Task", "Status"}},
+		{"task_id", []string{"task", "id"}},
+		{"CREDIT_CARD", []string{"CREDIT", "CARD"}},
+		{"billing-v2", []string{"billing", "v2"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"taskID", []string{"task", "ID"}},
+		{"task2Status", []string{"task2", "Status"}},
+		{"__weird__name__", []string{"weird", "name"}},
+		{"Billing V2", []string{"Billing", "V2"}},
+		{"", nil},
+		{"_", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Words(tt.input))
+		})
+	}
+}
+
+func TestConversions(t *testing.T) {
+	tests := []struct {
+		input          string
+		pascal         string
+		camel          string
+		snake          string
+		screamingSnake string
+		kebab          string
+	}{
+		{"task_status", "TaskStatus", "taskStatus", "task_status", "TASK_STATUS", "task-status"},
+		{"TaskStatus", "TaskStatus", "taskStatus", "task_status", "TASK_STATUS", "task-status"},
+		{"BANK_TRANSFER", "BankTransfer", "bankTransfer", "bank_transfer", "BANK_TRANSFER", "bank-transfer"},
+		{"correlationId", "CorrelationId", "correlationId", "correlation_id", "CORRELATION_ID", "correlation-id"},
+		{"billing-v2", "BillingV2", "billingV2", "billing_v2", "BILLING_V2", "billing-v2"},
+		{"HTTPServer", "HttpServer", "httpServer", "http_server", "HTTP_SERVER", "http-server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.pascal, Pascal(tt.input))
+			assert.Equal(t, tt.camel, Camel(tt.input))
+			assert.Equal(t, tt.snake, Snake(tt.input))
+			assert.Equal(t, tt.screamingSnake, ScreamingSnake(tt.input))
+			assert.Equal(t, tt.kebab, Kebab(tt.input))
+		})
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	assert.True(t, IsPascal("TaskStatus"))
+	assert.True(t, IsPascal("HTTPServer"))
+	assert.False(t, IsPascal("taskStatus"))
+	assert.False(t, IsPascal("Task_Status"))
+
+	assert.True(t, IsCamel("correlationId"))
+	assert.True(t, IsCamel("id"))
+	assert.False(t, IsCamel("CorrelationId"))
+	assert.False(t, IsCamel("correlation_id"))
+
+	assert.True(t, IsScreamingSnake("PENDING"))
+	assert.True(t, IsScreamingSnake("CREDIT_CARD"))
+	assert.True(t, IsScreamingSnake("HTTP2_ERROR"))
+	assert.False(t, IsScreamingSnake("CREDIT__CARD"))
+	assert.False(t, IsScreamingSnake("CREDIT_CARD_"))
+	assert.False(t, IsScreamingSnake("CreditCard"))
+}
@@ -0,0 +1,79 @@
+package diagnostic
+
+import (
+	"fmt"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Severity indicates how serious a Diagnostic is.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Diagnostic is a problem found in a contract, reported at a source position.
+type Diagnostic struct {
+	Pos      lexer.Position
+	Severity Severity
+	Message  string
+	// Rule is the name of the analyzer rule that reported the diagnostic, if any.
+	Rule string
+	// Fix is an optional suggested change that resolves the diagnostic.
+	Fix *Fix
+}
+
+// Fix is a suggested change that resolves a Diagnostic.
+type Fix struct {
+	// Message describes the fix, e.g. `rename "task_status" to "TaskStatus"`.
+	Message string
+	Edits   []Edit
+}
+
+// Edit replaces the Old text found at Pos with New.
+type Edit struct {
+	Pos lexer.Position
+	Old string
+	New string
+}
+
+// Errorf creates an error Diagnostic at the given position.
+func Errorf(pos lexer.Position, format string, args ...any) Diagnostic {
+	return Diagnostic{Pos: pos, Severity: SeverityError, Message: fmt.Sprintf(format, args...)}
+}
+
+// Warningf creates a warning Diagnostic at the given position.
+func Warningf(pos lexer.Position, format string, args ...any) Diagnostic {
+	return Diagnostic{Pos: pos, Severity: SeverityWarning, Message: fmt.Sprintf(format, args...)}
+}
+
+func (d Diagnostic) String() string {
+	s := fmt.Sprintf("%s: %s: %s", d.Pos, d.Severity, d.Message)
+	if d.Rule != "" {
+		s += " [" + d.Rule + "]"
+	}
+	return s
+}
+
+// HasErrors reports whether any of the diagnostics has error severity.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package diagnostic
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnosticString(t *testing.T) {
+	pos := lexer.Position{Filename: "tasks.ufoc", Line: 3, Column: 5}
+
+	assert.Equal(t, "tasks.ufoc:3:5: error: boom", Errorf(pos, "boom").String())
+	assert.Equal(t, "tasks.ufoc:3:5: warning: careful with 2", Warningf(pos, "careful with %d", 2).String())
+
+	d := Warningf(pos, "bad name")
+	d.Rule = "naming/type"
+	assert.Equal(t, "tasks.ufoc:3:5: warning: bad name [naming/type]", d.String())
+}
+
+func TestHasErrors(t *testing.T) {
+	assert.False(t, HasErrors(nil))
+	assert.False(t, HasErrors([]Diagnostic{Warningf(lexer.Position{}, "w")}))
+	assert.True(t, HasErrors([]Diagnostic{Warningf(lexer.Position{}, "w"), Errorf(lexer.Position{}, "e")}))
+}
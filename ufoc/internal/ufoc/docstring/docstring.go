@@ -0,0 +1,135 @@
+// Package docstring implements the docstring normalization described in
+// section 8.2 of the spec, so that consumers never have to deal with the raw
+// delimiters or the source indentation.
+package docstring
+
+import (
+	"strings"
+)
+
+const (
+	delimiter = `"""`
+	tabWidth  = 4
+)
+
+// Normalize turns a raw docstring token into its documentation text:
+//
+//   - The """ delimiters are stripped.
+//   - Leading and trailing blank lines are removed.
+//   - The indentation of the first non-empty line is the baseline and is
+//     removed from every line, preserving the relative Markdown indentation.
+//
+// Text written on the same line as the opening delimiter is not indented
+// relative to the source, so it is trimmed and the baseline is taken from the
+// next non-empty line instead. Tabs count up to the next multiple of four
+// columns when lines mix tabs and spaces.
+func Normalize(raw string) string {
+	content := strings.TrimPrefix(raw, delimiter)
+	content = strings.TrimSuffix(content, delimiter)
+
+	lines := splitLines(content)
+
+	first := strings.TrimSpace(lines[0])
+	rest := dedent(lines[1:])
+	if first != "" {
+		rest = append([]string{first}, rest...)
+	}
+
+	return strings.Join(trimBlankLines(rest), "\n")
+}
+
+// NormalizeMarkdown normalizes the content of an external Markdown file. The
+// file is used as written, only line endings are unified and leading and
+// trailing blank lines are removed.
+func NormalizeMarkdown(content string) string {
+	return strings.Join(trimBlankLines(splitLines(content)), "\n")
+}
+
+func splitLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Split(s, "\n")
+}
+
+func dedent(lines []string) []string {
+	baseline := -1
+	for _, line := range lines {
+		if isBlank(line) {
+			continue
+		}
+		baseline = indentWidth(line)
+		break
+	}
+
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if isBlank(line) {
+			continue
+		}
+		out[i] = stripIndent(line, baseline)
+	}
+	return out
+}
+
+// indentWidth returns the width in columns of the leading whitespace of line.
+func indentWidth(line string) int {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += tabWidth - width%tabWidth
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// stripIndent removes up to width columns of leading whitespace from line. A
+// tab that straddles the baseline is replaced by the spaces left over after it.
+func stripIndent(line string, width int) string {
+	col := 0
+	for i, r := range line {
+		if col >= width {
+			return line[i:]
+		}
+		switch r {
+		case ' ':
+			col++
+		case '\t':
+			col += tabWidth - col%tabWidth
+		default:
+			return line[i:]
+		}
+		if col > width {
+			return strings.Repeat(" ", col-width) + line[i+1:]
+		}
+	}
+	return ""
+}
+
+func trimBlankLines(lines []string) []string {
+	start, end := 0, len(lines)
+	for start < end && isBlank(lines[start]) {
+		start++
+	}
+	for end > start && isBlank(lines[end-1]) {
+		end--
+	}
+	lines = lines[start:end]
+
+	for i, line := range lines {
+		if isBlank(line) {
+			lines[i] = ""
+		}
+	}
+	if n := len(lines); n > 0 {
+		lines[n-1] = strings.TrimRight(lines[n-1], " \t")
+	}
+	return lines
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
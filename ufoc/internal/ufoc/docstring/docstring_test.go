@@ -0,0 +1,132 @@
+package docstring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{
+			name:     "single line",
+			raw:      `""" The name of the task. """`,
+			expected: "The name of the task.",
+		},
+		{
+			name:     "single line without spaces",
+			raw:      `"""Compact."""`,
+			expected: "Compact.",
+		},
+		{
+			name:     "empty",
+			raw:      `""""""`,
+			expected: "",
+		},
+		{
+			name:     "only whitespace",
+			raw:      "\"\"\"  \n\t\n  \"\"\"",
+			expected: "",
+		},
+		{
+			name:     "multi line with tabs",
+			raw:      "\"\"\"\n\t\tDocumentation for Tasks namespace.\n\t\t\"\"\"",
+			expected: "Documentation for Tasks namespace.",
+		},
+		{
+			name:     "multi line with spaces",
+			raw:      "\"\"\"\n    This file defines all data contracts\n    for the Tasks domain.\n    \"\"\"",
+			expected: "This file defines all data contracts\nfor the Tasks domain.",
+		},
+		{
+			name:     "leading and trailing blank lines",
+			raw:      "\"\"\"\n\n\t\n\tText.\n\n  \n\t\"\"\"",
+			expected: "Text.",
+		},
+		{
+			name:     "inner blank lines are kept and emptied",
+			raw:      "\"\"\"\n\t## Title\n\t\n\n\tParagraph.\n\t\"\"\"",
+			expected: "## Title\n\n\nParagraph.",
+		},
+		{
+			name:     "relative indentation is preserved",
+			raw:      "\"\"\"\n\t\tList:\n\t\t- one\n\t\t  - nested\n\n\t\t\tcode block\n\t\t\"\"\"",
+			expected: "List:\n- one\n  - nested\n\n\tcode block",
+		},
+		{
+			name:     "less indented lines lose what they have",
+			raw:      "\"\"\"\n\t\tFirst\n\tSecond\nThird\n\t\t\"\"\"",
+			expected: "First\nSecond\nThird",
+		},
+		{
+			name:     "tab baseline with space indented lines",
+			raw:      "\"\"\"\n\tFirst\n    Second\n      Third\n\t\"\"\"",
+			expected: "First\nSecond\n  Third",
+		},
+		{
+			name:     "space baseline with tab indented lines",
+			raw:      "\"\"\"\n    First\n\tSecond\n\t\tThird\n    \"\"\"",
+			expected: "First\nSecond\n\tThird",
+		},
+		{
+			name:     "tab straddling the baseline",
+			raw:      "\"\"\"\n  First\n\tSecond\n  \"\"\"",
+			expected: "First\n  Second",
+		},
+		{
+			name:     "mixed spaces and tabs in the same indentation",
+			raw:      "\"\"\"\n  \tFirst\n\t  Second\n    \t\tThird\n\"\"\"",
+			expected: "First\n  Second\n\t\tThird",
+		},
+		{
+			name:     "text on the opening line",
+			raw:      "\"\"\" Summary line.\n\t\tMore details\n\t\t  indented.\n\t\t\"\"\"",
+			expected: "Summary line.\nMore details\n  indented.",
+		},
+		{
+			name:     "text on the closing line",
+			raw:      "\"\"\"\n\t\tFirst\n\t\tLast   \"\"\"",
+			expected: "First\nLast",
+		},
+		{
+			name:     "windows line endings",
+			raw:      "\"\"\"\r\n\tFirst\r\n\t  Second\r\n\t\"\"\"",
+			expected: "First\n  Second",
+		},
+		{
+			name:     "quotes inside",
+			raw:      `""" Use "PENDING" or ""RUNNING"". """`,
+			expected: `Use "PENDING" or ""RUNNING"".`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Normalize(tt.raw))
+		})
+	}
+}
+
+func TestNormalizeMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"trailing newline", "# Overview\n", "# Overview"},
+		{"blank lines around", "\n\n# Overview\n\nText.\n\n\n", "# Overview\n\nText."},
+		{"indentation is kept", "    code\n- item\n  - nested\n", "    code\n- item\n  - nested"},
+		{"windows line endings", "# Title\r\n\r\nText.\r\n", "# Title\n\nText."},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeMarkdown(tt.content))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Package fake generates random instances of the types of a contract encoded
+// as JSON, for test fixtures and property-based tests. Values follow the
+// contract: required fields are always set, enums only take their members
+// and every primitive is in its wire format. The same seed always produces
+// the same values.
+package fake
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+const (
+	// maxDepth is the nesting depth after which optional fields are left out,
+	// nullable fields are null and arrays are empty, so recursive types stay
+	// small.
+	maxDepth = 4
+	// maxItems is the maximum length of generated arrays.
+	maxItems = 3
+	// recursionLimit is the nesting depth at which generation fails, reached
+	// by types that require themselves.
+	recursionLimit = 32
+)
+
+var words = []string{
+	"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliett", "kilo", "lima", "mike", "november", "oscar", "papa",
+}
+
+// epoch is the start of the range of generated dates and times.
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Generator generates instances of the types of an analyzed schema.
+type Generator struct {
+	rng         *rand.Rand
+	fieldOwners map[*ir.Field]*ir.Namespace
+	enumOwners  map[*ir.Enum]*ir.Namespace
+}
+
+// New returns a Generator for the analyzed schema seeded with seed.
+func New(schema *ir.Schema, seed uint64) *Generator {
+	return &Generator{
+		rng:         rand.New(rand.NewPCG(seed, seed)),
+		fieldOwners: schema.FieldNamespaces(),
+		enumOwners:  schema.EnumNamespaces(),
+	}
+}
+
+// Value returns a random instance of t, a type of ns, encoded as JSON with
+// the fields in declaration order.
+func (g *Generator) Value(ns *ir.Namespace, t *ir.Type) (json.RawMessage, error) {
+	if t.Generic() {
+		return nil, fmt.Errorf("type %q is generic, fake an instantiation of it instead", t.Name)
+	}
+	v, err := g.object(ns, t.AllFields(), 0)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// object is a JSON object that keeps the order of its members.
+type object []member
+
+type member struct {
+	key   string
+	value any
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (g *Generator) object(ns *ir.Namespace, fields []*ir.Field, depth int) (object, error) {
+	if depth >= recursionLimit {
+		return nil, fmt.Errorf("fields nested more than %d levels deep, the types are recursive", recursionLimit)
+	}
+
+	o := object{}
+	for _, f := range fields {
+		if f.Optional && (depth >= maxDepth || g.rng.IntN(4) == 0) {
+			continue
+		}
+		owner, ok := g.fieldOwners[f]
+		if !ok {
+			owner = ns
+		}
+		m := member{key: owner.WireName(f)}
+		if f.Nullable && (depth >= maxDepth || g.rng.IntN(5) == 0) {
+			o = append(o, m)
+			continue
+		}
+		v, err := g.value(owner, f.Type, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		m.value = v
+		o = append(o, m)
+	}
+	return o, nil
+}
+
+func (g *Generator) value(ns *ir.Namespace, ref *ir.TypeRef, depth int) (any, error) {
+	if ref.Array {
+		n := 0
+		if depth < maxDepth {
+			n = g.rng.IntN(maxItems + 1)
+		}
+		item := *ref
+		item.Array = false
+		items := make([]any, 0, n)
+		for range n {
+			v, err := g.value(ns, &item, depth)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	}
+
+	switch {
+	case ref.Inline():
+		return g.object(ns, ref.Fields, depth)
+	case ref.Instance != nil:
+		return g.object(ns, ref.Instance.Type.AllFields(), depth)
+	case ref.Type != nil && !ref.Type.Generic():
+		return g.object(ns, ref.Type.AllFields(), depth)
+	case ref.Enum != nil:
+		return g.enum(ref.Enum)
+	case ref.Primitive != "":
+		return g.primitive(ref.Primitive), nil
+	default:
+		return nil, fmt.Errorf("cannot generate a value of unresolved type %q", ref.Name)
+	}
+}
+
+func (g *Generator) enum(e *ir.Enum) (any, error) {
+	if len(e.Members) == 0 {
+		return nil, nil
+	}
+	if !e.Flags() {
+		m := e.Members[g.rng.IntN(len(e.Members))]
+		if e.BaseType != string(ir.Int) {
+			return m.WireValue(), nil
+		}
+		// The analyzer checks that the values of int enums fold to integer
+		// literals, which are valid JSON numbers.
+		if m.Value == nil || m.Value.Literal().Number == nil {
+			return nil, fmt.Errorf("member %q of int enum %q has no integer value", m.Name, e.Name)
+		}
+		return json.Number(*m.Value.Literal().Number), nil
+	}
+
+	var mask uint64
+	for _, m := range e.Members {
+		if g.rng.IntN(2) == 0 {
+			mask |= e.FlagValue(m)
+		}
+	}
+	if ns, ok := g.enumOwners[e]; ok && ns.FlagsEncoding(e) == ir.FlagsNames {
+		names, _ := e.FlagNames(mask)
+		if names == nil {
+			names = []string{}
+		}
+		return names, nil
+	}
+	return mask, nil
+}
+
+func (g *Generator) primitive(p ir.Primitive) any {
+	r := g.rng
+	switch p {
+	case ir.String:
+		n := 1 + r.IntN(3)
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = words[r.IntN(len(words))]
+		}
+		return strings.Join(parts, " ")
+	case ir.Int, ir.Int32:
+		return r.IntN(1000)
+	case ir.Float, ir.Float32:
+		return float64(r.IntN(100000)) / 100
+	case ir.Bool:
+		return r.IntN(2) == 0
+	case ir.Datetime:
+		return g.instant().Format(time.RFC3339)
+	case ir.Date:
+		return g.instant().Format(time.DateOnly)
+	case ir.Time:
+		return g.instant().Format(time.TimeOnly)
+	case ir.Duration:
+		return fmt.Sprintf("PT%dH%dM", r.IntN(48), r.IntN(60))
+	case ir.UUID:
+		var b [16]byte
+		for i := range b {
+			b[i] = byte(r.IntN(256))
+		}
+		// Version 4, variant 10.
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case ir.Bytes:
+		b := make([]byte, 4+r.IntN(13))
+		for i := range b {
+			b[i] = byte(r.IntN(256))
+		}
+		return base64.StdEncoding.EncodeToString(b)
+	case ir.Decimal:
+		return strconv.Itoa(r.IntN(10000)) + "." + fmt.Sprintf("%02d", r.IntN(100))
+	default:
+		return nil
+	}
+}
+
+// instant returns a random second within two years of epoch.
+func (g *Generator) instant() time.Time {
+	return epoch.Add(time.Duration(g.rng.Int64N(2*365*24*3600)) * time.Second)
+}
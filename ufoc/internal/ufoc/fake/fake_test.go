@@ -0,0 +1,141 @@
+package fake
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/analyzer"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+	"github.com/uforg/ufocontract/internal/ufoc/loader"
+)
+
+const contract = `
+	version 1
+	namespace Tasks {
+		option wire.case = "snake"
+
+		enum TaskStatus { PENDING RUNNING DONE }
+		enum Priority: int { LOW = 1 HIGH = Priority.LOW * 10 }
+
+		@json.flags("names")
+		enum Permission: flags { READ WRITE ADMIN }
+
+		type Page<T> { items: T[] }
+
+		type Task {
+			id: uuid
+			title: string
+			status: TaskStatus
+			priority: Priority
+			permissions: Permission
+			createdAt: datetime
+			dueDate?: date
+			reminder: time
+			estimate: duration
+			attachment: bytes
+			cost: decimal
+			done: bool
+			note: string | null
+			tags: string[]
+			parent?: Task
+			subtasks: Page<Task>
+		}
+	}
+`
+
+func load(t *testing.T) *ir.Schema {
+	t.Helper()
+	res, err := loader.LoadBytes(filepath.Join(t.TempDir(), "tasks.ufoc"), []byte(contract))
+	require.NoError(t, err)
+	diags := append(res.Diagnostics, analyzer.Analyze(res.Schema, analyzer.Config{})...)
+	require.False(t, diagnostic.HasErrors(diags), diags)
+	return res.Schema
+}
+
+func TestValueFollowsTheContract(t *testing.T) {
+	schema := load(t)
+	ns, task := schema.LookupType("Tasks.Task")
+	require.NotNil(t, task)
+
+	g := New(schema, 42)
+	for range 50 {
+		raw, err := g.Value(ns, task)
+		require.NoError(t, err)
+		checkTask(t, raw)
+	}
+}
+
+func checkTask(t *testing.T, raw json.RawMessage) {
+	t.Helper()
+	var task map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &task))
+
+	for _, key := range []string{"id", "title", "status", "created_at", "note", "tags", "subtasks"} {
+		assert.Contains(t, task, key)
+	}
+	strings := map[string]ir.Primitive{
+		"id": ir.UUID, "created_at": ir.Datetime, "due_date": ir.Date, "reminder": ir.Time,
+		"estimate": ir.Duration, "attachment": ir.Bytes, "cost": ir.Decimal,
+	}
+	for key, p := range strings {
+		var s string
+		if v, ok := task[key]; ok {
+			require.NoError(t, json.Unmarshal(v, &s))
+			assert.NoError(t, p.CheckString(s))
+		}
+	}
+
+	var status string
+	require.NoError(t, json.Unmarshal(task["status"], &status))
+	assert.Contains(t, []string{"PENDING", "RUNNING", "DONE"}, status)
+	var priority int
+	require.NoError(t, json.Unmarshal(task["priority"], &priority))
+	assert.Contains(t, []int{1, 10}, priority)
+	var permissions []string
+	require.NoError(t, json.Unmarshal(task["permissions"], &permissions))
+	for _, p := range permissions {
+		assert.True(t, slices.Contains([]string{"READ", "WRITE", "ADMIN"}, p), p)
+	}
+
+	var subtasks struct{ Items []json.RawMessage }
+	require.NoError(t, json.Unmarshal(task["subtasks"], &subtasks))
+	for _, sub := range subtasks.Items {
+		checkTask(t, sub)
+	}
+	if parent, ok := task["parent"]; ok {
+		checkTask(t, parent)
+	}
+}
+
+func TestValueIsDeterministic(t *testing.T) {
+	schema := load(t)
+	ns, task := schema.LookupType("Tasks.Task")
+
+	generate := func(seed uint64) []string {
+		g := New(schema, seed)
+		var out []string
+		for range 5 {
+			raw, err := g.Value(ns, task)
+			require.NoError(t, err)
+			out = append(out, string(raw))
+		}
+		return out
+	}
+
+	assert.Equal(t, generate(7), generate(7))
+	assert.NotEqual(t, generate(7), generate(8))
+}
+
+func TestValueOfGenericType(t *testing.T) {
+	schema := load(t)
+	ns, page := schema.LookupType("Tasks.Page")
+	require.NotNil(t, page)
+
+	_, err := New(schema, 1).Value(ns, page)
+	assert.EqualError(t, err, `type "Page" is generic, fake an instantiation of it instead`)
+}
@@ -0,0 +1,492 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	goimporter "go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+type goImporter struct {
+	file  *File
+	pkg   *types.Package
+	taken map[string]bool
+	// names maps the exported struct types and the enum types of the package
+	// to their imported names.
+	names map[*types.TypeName]string
+	// docs holds the doc comments of the declarations of the package.
+	docs map[token.Pos]string
+	// inlining holds the unexported struct types being inlined, to detect
+	// cycles.
+	inlining map[*types.TypeName]bool
+}
+
+// Go imports the Go package in dir into the namespace. Exported struct types
+// become types, with the field names of their json tags, and named string
+// and integer types with constants become enums. The package is type checked
+// from source, like its imports.
+func Go(dir, namespace string) (*File, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	im := &goImporter{
+		file:     &File{Namespace: namespace},
+		taken:    map[string]bool{},
+		names:    map[*types.TypeName]string{},
+		docs:     declarationDocs(files),
+		inlining: map[*types.TypeName]bool{},
+	}
+
+	var typeErrs []error
+	conf := types.Config{
+		Importer: goimporter.ForCompiler(fset, "source", nil),
+		Error:    func(err error) { typeErrs = append(typeErrs, err) },
+	}
+	im.pkg, _ = conf.Check(bp.ImportPath, fset, files, nil)
+	if len(typeErrs) > 0 {
+		im.file.Warnings = append(im.file.Warnings, fmt.Sprintf("the package has type errors, the fields using the types involved are left out: %s", typeErrs[0]))
+	}
+
+	structs, enums := im.declarations()
+	for _, obj := range structs {
+		im.names[obj] = TypeName(obj.Name(), im.taken)
+	}
+	for _, e := range enums {
+		im.names[e.obj] = TypeName(e.obj.Name(), im.taken)
+	}
+	for _, obj := range structs {
+		im.structType(obj)
+	}
+	for _, e := range enums {
+		im.enum(e.obj, e.consts)
+	}
+
+	if len(im.file.Types) == 0 && len(im.file.Enums) == 0 {
+		return nil, fmt.Errorf("package %s has no exported struct types or enums", bp.Name)
+	}
+	nameArrayObjects(im.file, im.taken)
+	return im.file, nil
+}
+
+// declarationDocs returns the doc comments of the type, constant and struct
+// field declarations, by the position of their name. The doc of a declaration
+// with a single spec is the doc of the spec.
+func declarationDocs(files []*ast.File) map[token.Pos]string {
+	docs := map[token.Pos]string{}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if field, ok := n.(*ast.Field); ok && field.Doc != nil {
+				for _, name := range field.Names {
+					docs[name.Pos()] = field.Doc.Text()
+				}
+			}
+			return true
+		})
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				doc := gen.Doc
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if spec.Doc != nil || len(gen.Specs) > 1 {
+						doc = spec.Doc
+					}
+					docs[spec.Name.Pos()] = doc.Text()
+				case *ast.ValueSpec:
+					if spec.Doc != nil || len(gen.Specs) > 1 {
+						doc = spec.Doc
+					}
+					for _, name := range spec.Names {
+						docs[name.Pos()] = doc.Text()
+					}
+				}
+			}
+		}
+	}
+	return docs
+}
+
+// enumType is a named type with constants.
+type enumType struct {
+	obj    *types.TypeName
+	consts []*types.Const
+}
+
+// declarations returns the exported struct types and the enum types of the
+// package, in source order.
+func (im *goImporter) declarations() ([]*types.TypeName, []enumType) {
+	scope := im.pkg.Scope()
+	var objects []types.Object
+	for _, name := range scope.Names() {
+		objects = append(objects, scope.Lookup(name))
+	}
+	slices.SortFunc(objects, func(a, b types.Object) int { return int(a.Pos() - b.Pos()) })
+
+	var structs []*types.TypeName
+	consts := map[*types.TypeName][]*types.Const{}
+	var enumTypes []*types.TypeName
+	for _, obj := range objects {
+		switch obj := obj.(type) {
+		case *types.TypeName:
+			if _, ok := obj.Type().Underlying().(*types.Struct); ok && obj.Exported() && !obj.IsAlias() {
+				structs = append(structs, obj)
+			}
+		case *types.Const:
+			named, ok := obj.Type().(*types.Named)
+			if !ok || named.Obj().Pkg() != im.pkg || enumBase(named) == "" {
+				continue
+			}
+			if _, seen := consts[named.Obj()]; !seen {
+				enumTypes = append(enumTypes, named.Obj())
+			}
+			consts[named.Obj()] = append(consts[named.Obj()], obj)
+		}
+	}
+
+	var enums []enumType
+	for _, obj := range enumTypes {
+		enums = append(enums, enumType{obj: obj, consts: consts[obj]})
+	}
+	slices.SortFunc(enums, func(a, b enumType) int { return int(a.obj.Pos() - b.obj.Pos()) })
+	return structs, enums
+}
+
+// enumBase returns the base of the enum a named type with constants becomes,
+// empty when it cannot be an enum.
+func enumBase(named *types.Named) string {
+	basic, ok := named.Underlying().(*types.Basic)
+	switch {
+	case !ok:
+		return ""
+	case basic.Info()&types.IsString != 0:
+		return string(ir.String)
+	case basic.Info()&types.IsInteger != 0:
+		return string(ir.Int)
+	default:
+		return ""
+	}
+}
+
+func (im *goImporter) structType(obj *types.TypeName) {
+	t := &Type{Name: im.names[obj], Doc: im.docs[obj.Pos()]}
+	// Type parameters cannot shadow the types of the namespace.
+	taken := maps.Clone(im.taken)
+	params := obj.Type().(*types.Named).TypeParams()
+	for i := range params.Len() {
+		p := params.At(i).Obj()
+		im.names[p] = TypeName(p.Name(), taken)
+		t.Params = append(t.Params, im.names[p])
+	}
+	t.Spreads, t.Fields, t.Warnings = im.fields(obj.Type().Underlying().(*types.Struct), map[string]bool{})
+	im.file.Types = append(im.file.Types, t)
+}
+
+// fields imports the fields of a struct as encoding/json encodes them,
+// naming them uniquely among taken. Embedded structs without a json tag
+// become spreads. The warnings are about the fields left out.
+func (im *goImporter) fields(st *types.Struct, taken map[string]bool) (spreads []string, fields []*Field, warnings []string) {
+	for i := range st.NumFields() {
+		v := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if tag == "-" || !v.Exported() && !v.Embedded() {
+			continue
+		}
+
+		if v.Embedded() && name == "" {
+			embedded := types.Unalias(deref(v.Type()))
+			named, _ := embedded.(*types.Named)
+			if st, ok := embedded.Underlying().(*types.Struct); ok && named != nil {
+				if spread, imported := im.names[named.Obj()]; imported {
+					spread, err := im.instance(named, spread, &warnings)
+					if err != nil {
+						warnings = append(warnings, fmt.Sprintf("embedded %s is left out: %s", named.Obj().Name(), err))
+						continue
+					}
+					spreads = append(spreads, spread)
+					continue
+				}
+				// The fields of other embedded structs of the package are
+				// promoted.
+				if named.Obj().Pkg() == im.pkg {
+					embeddedSpreads, embeddedFields, embeddedWarnings := im.fields(st, taken)
+					spreads = append(spreads, embeddedSpreads...)
+					fields = append(fields, embeddedFields...)
+					warnings = append(warnings, embeddedWarnings...)
+					continue
+				}
+			}
+			if !v.Exported() {
+				continue
+			}
+		}
+
+		if name == "" {
+			name = v.Name()
+		}
+		f := &Field{
+			Doc:      im.docs[v.Pos()],
+			WireName: &name,
+			Optional: hasOption(opts, "omitempty") || hasOption(opts, "omitzero"),
+		}
+		// encoding/json writes nil pointers and slices as null, and leaves
+		// nil slices out with omitempty or omitzero.
+		switch v.Type().Underlying().(type) {
+		case *types.Pointer:
+			f.Nullable = true
+		case *types.Slice:
+			f.Nullable = !f.Optional
+		}
+		if hasOption(opts, "string") {
+			f.Warnings = append(f.Warnings, `the ",string" option is not supported, the value is imported as its Go type`)
+		}
+
+		ref, err := im.typeRef(v.Type(), &f.Warnings)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("field %q is left out: %s", name, err))
+			continue
+		}
+		f.Name = FieldName(name, taken)
+		f.Type = ref
+		fields = append(fields, f)
+	}
+	return spreads, fields, warnings
+}
+
+func hasOption(opts, name string) bool {
+	return slices.Contains(strings.Split(opts, ","), name)
+}
+
+func deref(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+// typeRef imports a Go type as encoding/json encodes it. Information that is
+// lost is added to warnings, while types that cannot be represented at all
+// return an error.
+func (im *goImporter) typeRef(t types.Type, warnings *[]string) (*TypeRef, error) {
+	t = deref(t)
+	// json.RawMessage is an alias with some GOEXPERIMENT settings.
+	if alias, ok := t.(*types.Alias); ok && isType(alias.Obj(), "encoding/json", "RawMessage") {
+		return nil, errors.New("json.RawMessage accepts any value")
+	}
+	t = types.Unalias(t)
+	if p, ok := t.(*types.TypeParam); ok {
+		if name, ok := im.names[p.Obj()]; ok {
+			return &TypeRef{Name: name}, nil
+		}
+	}
+
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if name, ok := im.names[obj]; ok {
+			name, err := im.instance(named, name, warnings)
+			if err != nil {
+				return nil, err
+			}
+			return &TypeRef{Name: name}, nil
+		}
+		switch {
+		case isType(obj, "time", "Time"):
+			return &TypeRef{Name: string(ir.Datetime)}, nil
+		case isType(obj, "time", "Duration"):
+			*warnings = append(*warnings, "time.Duration is encoded as an integer number of nanoseconds")
+			return &TypeRef{Name: string(ir.Int)}, nil
+		case isType(obj, "encoding/json", "RawMessage"):
+			return nil, errors.New("json.RawMessage accepts any value")
+		case implements(t, "MarshalJSON"):
+			return nil, fmt.Errorf("%s has a custom JSON encoding", types.TypeString(t, im.qualifier))
+		case implements(t, "MarshalText"):
+			if strings.EqualFold(obj.Name(), "UUID") {
+				return &TypeRef{Name: string(ir.UUID)}, nil
+			}
+			*warnings = append(*warnings, fmt.Sprintf("%s is encoded as text, imported as a string", types.TypeString(t, im.qualifier)))
+			return &TypeRef{Name: string(ir.String)}, nil
+		}
+		if st, ok := named.Underlying().(*types.Struct); ok {
+			if obj.Pkg() != im.pkg {
+				return nil, fmt.Errorf("struct types of other packages are not supported, import %s separately", types.TypeString(t, im.qualifier))
+			}
+			if im.inlining[obj] {
+				return nil, fmt.Errorf("unexported type %s refers to itself", obj.Name())
+			}
+			im.inlining[obj] = true
+			defer delete(im.inlining, obj)
+			return im.inline(st, warnings)
+		}
+	}
+
+	switch t := t.Underlying().(type) {
+	case *types.Basic:
+		return basicType(t)
+	case *types.Slice, *types.Array:
+		elem := t.(interface{ Elem() types.Type }).Elem()
+		if basic, ok := elem.Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			if _, ok := t.(*types.Slice); ok {
+				return &TypeRef{Name: string(ir.Bytes)}, nil
+			}
+		}
+		ref, err := im.typeRef(elem, warnings)
+		if err != nil {
+			return nil, err
+		}
+		if ref.Array {
+			return nil, errors.New("nested slices are not supported")
+		}
+		ref.Array = true
+		return ref, nil
+	case *types.Struct:
+		return im.inline(t, warnings)
+	case *types.Map:
+		return nil, errors.New("maps are not supported")
+	case *types.Interface:
+		return nil, errors.New("interfaces accept any value")
+	default:
+		return nil, fmt.Errorf("type %s is not supported", types.TypeString(t, im.qualifier))
+	}
+}
+
+// instance returns the imported name of a named type with its type arguments,
+// e.g. Page<Task> for Page[*Task].
+func (im *goImporter) instance(named *types.Named, name string, warnings *[]string) (string, error) {
+	targs := named.TypeArgs()
+	if targs.Len() == 0 {
+		return name, nil
+	}
+	var args []string
+	for i := range targs.Len() {
+		ref, err := im.typeRef(targs.At(i), warnings)
+		if err != nil {
+			return "", err
+		}
+		if ref.Name == "" {
+			return "", errors.New("inline objects cannot be type arguments")
+		}
+		if ref.Array {
+			ref.Name += "[]"
+		}
+		args = append(args, ref.Name)
+	}
+	return name + "<" + strings.Join(args, ", ") + ">", nil
+}
+
+// inline imports an anonymous or unexported struct as an inline object.
+func (im *goImporter) inline(st *types.Struct, warnings *[]string) (*TypeRef, error) {
+	spreads, fields, fieldWarnings := im.fields(st, map[string]bool{})
+	if len(spreads) > 0 {
+		return nil, errors.New("embedded structs are not supported in inline objects")
+	}
+	*warnings = append(*warnings, fieldWarnings...)
+	return &TypeRef{Fields: fields}, nil
+}
+
+func basicType(b *types.Basic) (*TypeRef, error) {
+	var p ir.Primitive
+	switch b.Kind() {
+	case types.String:
+		p = ir.String
+	case types.Bool:
+		p = ir.Bool
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16:
+		p = ir.Int32
+	case types.Int, types.Int64, types.Uint, types.Uint32, types.Uint64, types.Uintptr:
+		p = ir.Int
+	case types.Float32:
+		p = ir.Float32
+	case types.Float64:
+		p = ir.Float
+	default:
+		return nil, fmt.Errorf("type %s is not supported", b)
+	}
+	return &TypeRef{Name: string(p)}, nil
+}
+
+// implements reports whether t or a pointer to it has the method.
+func implements(t types.Type, method string) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, method)
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// isType reports whether obj is the type with the name in the package with
+// the path.
+func isType(obj types.Object, path, name string) bool {
+	return obj.Pkg() != nil && obj.Pkg().Path() == path && obj.Name() == name
+}
+
+// qualifier writes the types of the imported package unqualified in
+// messages.
+func (im *goImporter) qualifier(pkg *types.Package) string {
+	if pkg == im.pkg {
+		return ""
+	}
+	return pkg.Name()
+}
+
+// enum imports a named type with constants. Members are named after the
+// constants without the type name prefix (StatusActive becomes ACTIVE).
+func (im *goImporter) enum(obj *types.TypeName, consts []*types.Const) {
+	named := obj.Type().(*types.Named)
+	e := &Enum{Name: im.names[obj], Doc: im.docs[obj.Pos()]}
+	if enumBase(named) == string(ir.Int) {
+		e.Base = string(ir.Int)
+	}
+
+	taken := map[string]bool{}
+	values := map[string]string{}
+	for _, c := range consts {
+		value := c.Val().ExactString()
+		if c.Val().Kind() == constant.String {
+			value = constant.StringVal(c.Val())
+		}
+		if prev, ok := values[value]; ok {
+			e.Warnings = append(e.Warnings, fmt.Sprintf("constant %s has the value of %s and is left out", c.Name(), prev))
+			continue
+		}
+		values[value] = c.Name()
+
+		name := strings.TrimPrefix(c.Name(), obj.Name())
+		if name == "" || !identRe.MatchString(name) {
+			name = c.Name()
+		}
+		m := &EnumMember{Doc: im.docs[c.Pos()], Name: MemberName(name, taken)}
+		switch {
+		case e.Base == string(ir.Int):
+			m.Value = value
+		case m.Name != value:
+			m.Value = Quote(value)
+		}
+		e.Members = append(e.Members, m)
+	}
+	im.file.Enums = append(im.file.Enums, e)
+}
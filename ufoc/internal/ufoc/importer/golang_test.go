@@ -0,0 +1,293 @@
+package importer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/validate"
+)
+
+// writePackage writes a Go module holding a package with the source and
+// returns the directory of the package.
+func writePackage(t *testing.T, src string) string {
+	t.Helper()
+	root := t.TempDir()
+	dir := filepath.Join(root, "dto")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/shop\n\ngo 1.25\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dto.go"), []byte(src), 0o644))
+	return dir
+}
+
+func TestGo(t *testing.T) {
+	dir := writePackage(t, `// Package dto holds the shop DTOs.
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Status is the lifecycle of an order.
+type Status string
+
+const (
+	// StatusPending orders are not paid yet.
+	StatusPending Status = "pending"
+	StatusPaid    Status = "paid"
+	StatusShipped Status = "SHIPPED"
+	StatusLegacy  Status = "paid"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityHigh
+)
+
+type audit struct {
+	CreatedBy string    `+"`json:\"created_by\"`"+`
+	CreatedAt time.Time `+"`json:\"created_at\"`"+`
+}
+
+// Base is shared by the entities.
+type Base struct {
+	ID string `+"`json:\"id\"`"+`
+}
+
+// Order is a customer order.
+type Order struct {
+	Base
+	audit
+	// Customer who placed the order.
+	Customer *Customer        `+"`json:\"customer\"`"+`
+	Coupon   *string          `+"`json:\"coupon,omitempty\"`"+`
+	Status   Status           `+"`json:\"status\"`"+`
+	Priority Priority         `+"`json:\"priority,omitempty\"`"+`
+	Lines    []Line           `+"`json:\"lines\"`"+`
+	Notes    string           `+"`json:\"notes,omitempty\"`"+`
+	Total    int64            `+"`json:\"total,string\"`"+`
+	Timeout  time.Duration    `+"`json:\"timeout\"`"+`
+	Raw      json.RawMessage  `+"`json:\"raw\"`"+`
+	Labels   map[string]string `+"`json:\"labels\"`"+`
+	Shipping struct {
+		Address string `+"`json:\"address\"`"+`
+		Express bool   `+"`json:\"express\"`"+`
+	} `+"`json:\"shipping\"`"+`
+	Signature []byte `+"`json:\"signature\"`"+`
+	Parcels   []struct {
+		Weight float64 `+"`json:\"weight\"`"+`
+	} `+"`json:\"parcels\"`"+`
+	internal  string
+	Ignored   string `+"`json:\"-\"`"+`
+}
+
+type Customer struct {
+	Name  string
+	Email string `+"`json:\",omitempty\"`"+`
+}
+
+type Line struct {
+	SKU      string  `+"`json:\"sku\"`"+`
+	Quantity int32   `+"`json:\"quantity\"`"+`
+	Price    float64 `+"`json:\"price\"`"+`
+}
+`)
+
+	file, err := Go(dir, "Shop")
+	require.NoError(t, err)
+	src := Format(file)
+	assert.Equal(t, `version 1
+
+namespace Shop {
+  option wire.case = "snake"
+
+  """ Base is shared by the entities. """
+  type Base {
+    id: string
+  }
+
+  // WARNING: field "raw" is left out: json.RawMessage accepts any value
+  // WARNING: field "labels" is left out: maps are not supported
+  // WARNING: field "total": the ",string" option is not supported, the value is imported as its Go type
+  // WARNING: field "timeout": time.Duration is encoded as an integer number of nanoseconds
+  """ Order is a customer order. """
+  type Order {
+    ...Base
+    createdBy: string
+    createdAt: datetime
+    """ Customer who placed the order. """
+    customer: Customer | null
+    coupon?: string | null
+    status: Status
+    priority?: Priority
+    lines: Line[] | null
+    notes?: string
+    total: int
+    timeout: int
+    shipping: {
+      address: string
+      express: bool
+    }
+    signature: bytes | null
+    parcels: OrderParcel[] | null
+  }
+
+  type OrderParcel {
+    weight: float
+  }
+
+  type Customer {
+    @json.name("Name")
+    name: string
+    @json.name("Email")
+    email?: string
+  }
+
+  type Line {
+    sku: string
+    quantity: int32
+    price: float
+  }
+
+  // WARNING: constant StatusLegacy has the value of StatusPaid and is left out
+  """ Status is the lifecycle of an order. """
+  enum Status {
+    """ StatusPending orders are not paid yet. """
+    PENDING = "pending"
+    PAID = "paid"
+    SHIPPED
+  }
+
+  enum Priority: int {
+    LOW = 1
+    HIGH = 2
+  }
+}
+`, string(src))
+	checkSource(t, src)
+}
+
+func TestGoGenerics(t *testing.T) {
+	dir := writePackage(t, `package dto
+
+type Page[T any] struct {
+	Items []T   `+"`json:\"items\"`"+`
+	Next  *Page[T] `+"`json:\"next\"`"+`
+}
+
+type Paged[T any] struct {
+	Total int `+"`json:\"total\"`"+`
+	Items []T `+"`json:\"items\"`"+`
+}
+
+type pair[K comparable, V any] struct {
+	Key   K `+"`json:\"key\"`"+`
+	Value V `+"`json:\"value\"`"+`
+}
+
+type Order struct {
+	ID string `+"`json:\"id\"`"+`
+}
+
+type Orders struct {
+	Paged[*Order]
+	Pages Page[Order]         `+"`json:\"pages\"`"+`
+	Pair  pair[string, Order] `+"`json:\"pair\"`"+`
+}
+`)
+
+	file, err := Go(dir, "Shop")
+	require.NoError(t, err)
+	src := Format(file)
+	assert.Equal(t, `version 1
+
+namespace Shop {
+  type Page<T> {
+    items: T[] | null
+    next: Page<T> | null
+  }
+
+  type Paged<T> {
+    total: int
+    items: T[] | null
+  }
+
+  type Order {
+    id: string
+  }
+
+  type Orders {
+    ...Paged<Order>
+    pages: Page<Order>
+    pair: {
+      key: string
+      value: Order
+    }
+  }
+}
+`, string(src))
+	checkSource(t, src)
+}
+
+// zeroPage and zeroOrder are the Page and Order types of TestGoZeroValue,
+// whose zero values are encoded with encoding/json.
+type zeroPage[T any] struct {
+	Items []T          `json:"items"`
+	Next  *zeroPage[T] `json:"next"`
+}
+
+type zeroOrder struct {
+	ID     string           `json:"id"`
+	Note   *string          `json:"note"`
+	Coupon *string          `json:"coupon,omitempty"`
+	Tags   []string         `json:"tags"`
+	Labels []string         `json:"labels,omitempty"`
+	Page   zeroPage[string] `json:"page"`
+}
+
+func TestGoZeroValue(t *testing.T) {
+	dir := writePackage(t, `package dto
+
+type Page[T any] struct {
+	Items []T      `+"`json:\"items\"`"+`
+	Next  *Page[T] `+"`json:\"next\"`"+`
+}
+
+type Order struct {
+	ID     string       `+"`json:\"id\"`"+`
+	Note   *string      `+"`json:\"note\"`"+`
+	Coupon *string      `+"`json:\"coupon,omitempty\"`"+`
+	Tags   []string     `+"`json:\"tags\"`"+`
+	Labels []string     `+"`json:\"labels,omitempty\"`"+`
+	Page   Page[string] `+"`json:\"page\"`"+`
+}
+`)
+
+	file, err := Go(dir, "Shop")
+	require.NoError(t, err)
+	contract, err := validate.LoadBytes(filepath.Join(t.TempDir(), "shop.ufoc"), Format(file))
+	require.NoError(t, err)
+	v, err := contract.Validator("Shop.Order")
+	require.NoError(t, err)
+
+	doc, err := json.Marshal(zeroOrder{})
+	require.NoError(t, err)
+	violations, err := v.Validate(doc)
+	require.NoError(t, err)
+	assert.Empty(t, violations, string(doc))
+}
+
+func TestGoErrors(t *testing.T) {
+	_, err := Go(t.TempDir(), "Shop")
+	assert.ErrorContains(t, err, "no buildable Go source files")
+
+	dir := writePackage(t, "package dto\n\ntype status string\n")
+	_, err = Go(dir, "Shop")
+	assert.EqualError(t, err, "package dto has no exported struct types or enums")
+}
@@ -0,0 +1,422 @@
+// Package importer converts schemas written in other languages, or sample
+// documents, into .ufoc source, to adopt UFO Contract incrementally.
+// Importers build a File, which Format prints as formatted source. What an
+// importer cannot represent is kept as a warning comment in the output, next
+// to the definition it concerns.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/uforg/ufocontract/internal/ufoc/casing"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// File is an imported contract with a single namespace.
+type File struct {
+	// Comment is printed after the version line, e.g. where the file was
+	// imported from.
+	Comment   string
+	Namespace string
+	Warnings  []string
+	Types     []*Type
+	Enums     []*Enum
+}
+
+// Type is an imported type definition.
+type Type struct {
+	Doc  string
+	Name string
+	// Params are the type parameters of a generic type.
+	Params   []string
+	Warnings []string
+	// Spreads are the names of the types spread into the type.
+	Spreads []string
+	Fields  []*Field
+}
+
+// Field is a field of an imported type or inline object.
+type Field struct {
+	Doc      string
+	Warnings []string
+	Name     string
+	// WireName is the name of the field in encoded data, which Format keeps
+	// with a wire.case option or a @json.name annotation when it differs
+	// from Name. It is nil when unknown, and can be the empty string.
+	WireName *string
+	Optional bool
+	Nullable bool
+	Type     *TypeRef
+	// Default is the default value as .ufoc source, empty when there is none.
+	Default string
+}
+
+// TypeRef is the type of an imported field: a named type or an inline object,
+// possibly an array.
+type TypeRef struct {
+	// Name is the named type as written, with the type arguments of generic
+	// types, e.g. Page<Task>. It is empty for inline objects.
+	Name   string
+	Fields []*Field
+	Array  bool
+}
+
+// Enum is an imported enum definition.
+type Enum struct {
+	Doc      string
+	Name     string
+	Warnings []string
+	// Base is the base type of the enum, empty for string enums.
+	Base    string
+	Members []*EnumMember
+}
+
+// EnumMember is a member of an imported enum.
+type EnumMember struct {
+	Doc  string
+	Name string
+	// Value is the explicit value as .ufoc source, empty when the member is
+	// encoded as its name.
+	Value string
+}
+
+// WarningCount returns the number of warnings of the file.
+func (f *File) WarningCount() int {
+	n := len(f.Warnings)
+	for _, t := range f.Types {
+		n += len(t.Warnings) + len(fieldWarnings("", t.Fields))
+	}
+	for _, e := range f.Enums {
+		n += len(e.Warnings)
+	}
+	return n
+}
+
+// fieldWarnings returns the warnings of fields and of the fields of their
+// inline objects, prefixed with the path of the field, since comments cannot
+// be written inside a type body.
+func fieldWarnings(prefix string, fields []*Field) []string {
+	var out []string
+	for _, f := range fields {
+		path := prefix + f.Name
+		for _, w := range f.Warnings {
+			out = append(out, fmt.Sprintf("field %q: %s", path, w))
+		}
+		out = append(out, fieldWarnings(path+".", f.Type.Fields)...)
+	}
+	return out
+}
+
+// nameArrayObjects turns the inline objects of arrays, which .ufoc cannot
+// write, into types named after the type and the field holding them, like
+// OrderLine for the lines field of Order. The new types follow the type
+// holding them. taken holds the names in use.
+func nameArrayObjects(f *File, taken map[string]bool) {
+	var types []*Type
+	var visit func(owner string, fields []*Field)
+	visit = func(owner string, fields []*Field) {
+		for _, fd := range fields {
+			switch {
+			case fd.Type.Array && fd.Type.Fields != nil:
+				t := &Type{Name: TypeName(owner+" "+singular(fd.Name), taken), Fields: fd.Type.Fields}
+				fd.Type = &TypeRef{Name: t.Name, Array: true}
+				types = append(types, t)
+				visit(t.Name, t.Fields)
+			case fd.Type.Fields != nil:
+				visit(owner+" "+fd.Name, fd.Type.Fields)
+			}
+		}
+	}
+
+	var out []*Type
+	for _, t := range f.Types {
+		types = []*Type{t}
+		visit(t.Name, t.Fields)
+		out = append(out, types...)
+	}
+	f.Types = out
+}
+
+// singular returns the singular of an English plural name, like line for
+// lines, to name array items.
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"), strings.HasSuffix(s, "shes"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss") && !strings.HasSuffix(s, "us") && !strings.HasSuffix(s, "is"):
+		return strings.TrimSuffix(s, "s")
+	default:
+		return s
+	}
+}
+
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Format prints f as formatted .ufoc source. Field wire names that differ
+// from the field names are kept with the wire.case option that covers most of
+// them and @json.name annotations for the others.
+func Format(f *File) []byte {
+	p := &printer{}
+	p.line("version 1")
+	p.line("")
+	if f.Comment != "" {
+		p.comment(f.Comment)
+	}
+	for _, w := range f.Warnings {
+		p.comment("WARNING: " + w)
+	}
+	if f.Comment != "" || len(f.Warnings) > 0 {
+		p.line("")
+	}
+
+	name := f.Namespace
+	if !identRe.MatchString(name) {
+		name = Quote(name)
+	}
+	p.line("namespace %s {", name)
+	p.indent++
+
+	p.wireCase = wireCase(f)
+	blank := false
+	if p.wireCase != ir.CasePreserve {
+		p.line("option wire.case = %q", p.wireCase)
+		blank = true
+	}
+	for _, t := range f.Types {
+		if blank {
+			p.line("")
+		}
+		p.typeDef(t)
+		blank = true
+	}
+	for _, e := range f.Enums {
+		if blank {
+			p.line("")
+		}
+		p.enumDef(e)
+		blank = true
+	}
+
+	p.indent--
+	p.line("}")
+	return p.buf.Bytes()
+}
+
+type printer struct {
+	buf      bytes.Buffer
+	indent   int
+	wireCase string
+}
+
+func (p *printer) line(format string, args ...any) {
+	s := fmt.Sprintf(format, args...)
+	if s != "" {
+		p.buf.WriteString(strings.Repeat("  ", p.indent))
+		p.buf.WriteString(s)
+	}
+	p.buf.WriteByte('\n')
+}
+
+// comment prints a line comment, one per line of text.
+func (p *printer) comment(text string) {
+	for _, l := range strings.Split(text, "\n") {
+		p.line("// %s", strings.TrimSpace(l))
+	}
+}
+
+// doc prints a docstring, on a single line when the text allows it.
+func (p *printer) doc(text string) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, `"""`, `'''`))
+	if text == "" {
+		return
+	}
+	if !strings.ContainsAny(text, "\n\"") {
+		p.line(`""" %s """`, text)
+		return
+	}
+	p.line(`"""`)
+	for _, l := range strings.Split(text, "\n") {
+		p.line("%s", strings.TrimRight(l, " \t"))
+	}
+	p.line(`"""`)
+}
+
+func (p *printer) typeDef(t *Type) {
+	for _, w := range append(t.Warnings, fieldWarnings("", t.Fields)...) {
+		p.comment("WARNING: " + w)
+	}
+	p.doc(t.Doc)
+	if len(t.Params) > 0 {
+		p.line("type %s<%s> {", t.Name, strings.Join(t.Params, ", "))
+	} else {
+		p.line("type %s {", t.Name)
+	}
+	p.indent++
+	for _, s := range t.Spreads {
+		p.line("...%s", s)
+	}
+	p.fields(t.Fields)
+	p.indent--
+	p.line("}")
+}
+
+func (p *printer) fields(fields []*Field) {
+	for _, f := range fields {
+		p.doc(f.Doc)
+		if f.WireName != nil && *f.WireName != ir.ApplyCase(p.wireCase, f.Name) {
+			p.line("@json.name(%s)", Quote(*f.WireName))
+		}
+
+		var suffix strings.Builder
+		if f.Type.Array {
+			suffix.WriteString("[]")
+		}
+		if f.Nullable {
+			suffix.WriteString(" | null")
+		}
+		if f.Default != "" {
+			suffix.WriteString(" = " + f.Default)
+		}
+		optional := ""
+		if f.Optional {
+			optional = "?"
+		}
+
+		if f.Type.Name != "" {
+			p.line("%s%s: %s%s", f.Name, optional, f.Type.Name, suffix.String())
+			continue
+		}
+		p.line("%s%s: {", f.Name, optional)
+		p.indent++
+		p.fields(f.Type.Fields)
+		p.indent--
+		p.line("}%s", suffix.String())
+	}
+}
+
+func (p *printer) enumDef(e *Enum) {
+	for _, w := range e.Warnings {
+		p.comment("WARNING: " + w)
+	}
+	p.doc(e.Doc)
+	if e.Base != "" {
+		p.line("enum %s: %s {", e.Name, e.Base)
+	} else {
+		p.line("enum %s {", e.Name)
+	}
+	p.indent++
+	for _, m := range e.Members {
+		p.doc(m.Doc)
+		if m.Value != "" {
+			p.line("%s = %s", m.Name, m.Value)
+		} else {
+			p.line("%s", m.Name)
+		}
+	}
+	p.indent--
+	p.line("}")
+}
+
+// wireCase returns the wire case matching the wire names of most fields,
+// preferring CasePreserve.
+func wireCase(f *File) string {
+	var fields []*Field
+	var collect func([]*Field)
+	collect = func(fs []*Field) {
+		for _, fd := range fs {
+			if fd.WireName != nil {
+				fields = append(fields, fd)
+			}
+			collect(fd.Type.Fields)
+		}
+	}
+	for _, t := range f.Types {
+		collect(t.Fields)
+	}
+
+	best, bestCount := ir.CasePreserve, -1
+	for _, c := range []string{ir.CasePreserve, ir.CaseCamel, ir.CaseSnake, ir.CaseKebab, ir.CasePascal} {
+		count := 0
+		for _, fd := range fields {
+			if ir.ApplyCase(c, fd.Name) == *fd.WireName {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = c, count
+		}
+	}
+	return best
+}
+
+// TypeName returns a PascalCase type or enum name derived from s, which is
+// not one of taken. The name is added to taken.
+func TypeName(s string, taken map[string]bool) string {
+	return unique(identifier(casing.Pascal(s), "Type"), taken)
+}
+
+// FieldName returns a camelCase field name derived from the wire name s,
+// which is not one of taken. The name is added to taken.
+func FieldName(s string, taken map[string]bool) string {
+	return unique(identifier(casing.Camel(s), "field"), taken)
+}
+
+// MemberName returns an UPPER_SNAKE_CASE enum member name derived from the
+// value s, which is not one of taken. The name is added to taken.
+func MemberName(s string, taken map[string]bool) string {
+	if strings.HasPrefix(s, "-") {
+		s = "minus " + s[1:]
+	}
+	return unique(identifier(casing.ScreamingSnake(s), "VALUE"), taken)
+}
+
+// identifier returns s without the characters identifiers cannot contain,
+// prefixed with prefix when it starts with a digit, or prefix alone when
+// nothing is left.
+func identifier(s, prefix string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '_' || r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, s)
+	switch {
+	case s == "":
+		return prefix
+	case s[0] >= '0' && s[0] <= '9':
+		sep := ""
+		if strings.ToUpper(prefix) == prefix {
+			sep = "_"
+		}
+		return prefix + sep + s
+	default:
+		return s
+	}
+}
+
+func unique(name string, taken map[string]bool) string {
+	candidate := name
+	for i := 2; taken[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	taken[candidate] = true
+	return candidate
+}
+
+// Quote returns s as a .ufoc string literal, which uses the escapes of JSON.
+func Quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/analyzer"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+	"github.com/uforg/ufocontract/internal/ufoc/loader"
+	"github.com/uforg/ufocontract/internal/ufoc/parser"
+)
+
+// checkSource checks that imported source parses and has no errors or
+// warnings.
+func checkSource(t *testing.T, src []byte) {
+	t.Helper()
+	_, err := parser.Parser.ParseBytes("imported.ufoc", src)
+	require.NoError(t, err, string(src))
+
+	res, err := loader.LoadBytes(filepath.Join(t.TempDir(), "imported.ufoc"), src)
+	require.NoError(t, err)
+	diags := append(res.Diagnostics, analyzer.Analyze(res.Schema, analyzer.Config{})...)
+	var messages []string
+	for _, d := range diags {
+		messages = append(messages, d.String())
+	}
+	assert.Empty(t, messages, string(src))
+	assert.False(t, diagnostic.HasErrors(diags))
+}
+
+func wire(name string) *string {
+	return &name
+}
+
+func TestFormat(t *testing.T) {
+	file := &File{
+		Comment:   "Imported from tasks.json.",
+		Namespace: "tasks-v2",
+		Warnings:  []string{"something was lost"},
+		Types: []*Type{
+			{
+				Doc:      "A unit of work.",
+				Name:     "Task",
+				Warnings: []string{"the type is open"},
+				Spreads:  []string{"Audit"},
+				Fields: []*Field{
+					{Name: "taskId", WireName: wire("task_id"), Type: &TypeRef{Name: "uuid"}},
+					{Name: "createdAt", WireName: wire("created_at"), Type: &TypeRef{Name: "datetime"}},
+					{Name: "eTag", WireName: wire("ETag"), Type: &TypeRef{Name: "string"}, Optional: true},
+					{
+						Doc:      "Line one.\nLine \"two\".",
+						Name:     "notes",
+						WireName: wire("notes"),
+						Nullable: true,
+						Type:     &TypeRef{Name: "string", Array: true},
+						Warnings: []string{"constraints are not supported"},
+					},
+					{
+						Name:     "retry",
+						WireName: wire("retry"),
+						Optional: true,
+						Type: &TypeRef{Fields: []*Field{
+							{Name: "maxAttempts", WireName: wire("max_attempts"), Type: &TypeRef{Name: "int"}, Default: "3", Warnings: []string{"minimum is lost"}},
+						}},
+					},
+				},
+			},
+			{Name: "Audit", Fields: []*Field{
+				{Name: "createdBy", WireName: wire("created_by"), Type: &TypeRef{Name: "string"}},
+				{Name: "field", WireName: wire(""), Type: &TypeRef{Name: "string"}},
+			}},
+		},
+		Enums: []*Enum{
+			{Name: "Status", Members: []*EnumMember{{Name: "OPEN", Doc: "Not done."}, {Name: "IN_PROGRESS", Value: `"in-progress"`}}},
+			{Name: "Priority", Base: "int", Members: []*EnumMember{{Name: "LOW", Value: "1"}}},
+		},
+	}
+
+	src := Format(file)
+	assert.Equal(t, `version 1
+
+// Imported from tasks.json.
+// WARNING: something was lost
+
+namespace "tasks-v2" {
+  option wire.case = "snake"
+
+  // WARNING: the type is open
+  // WARNING: field "notes": constraints are not supported
+  // WARNING: field "retry.maxAttempts": minimum is lost
+  """ A unit of work. """
+  type Task {
+    ...Audit
+    taskId: uuid
+    createdAt: datetime
+    @json.name("ETag")
+    eTag?: string
+    """
+    Line one.
+    Line "two".
+    """
+    notes: string[] | null
+    retry?: {
+      maxAttempts: int = 3
+    }
+  }
+
+  type Audit {
+    createdBy: string
+    @json.name("")
+    field: string
+  }
+
+  enum Status {
+    """ Not done. """
+    OPEN
+    IN_PROGRESS = "in-progress"
+  }
+
+  enum Priority: int {
+    LOW = 1
+  }
+}
+`, string(src))
+	assert.Equal(t, 4, file.WarningCount())
+	checkSource(t, src)
+}
+
+func TestNames(t *testing.T) {
+	taken := map[string]bool{}
+	assert.Equal(t, "InvoiceLine", TypeName("invoice_line", taken))
+	assert.Equal(t, "InvoiceLine2", TypeName("invoice-line", taken))
+	assert.Equal(t, "Type3d", TypeName("3d", taken))
+	assert.Equal(t, "Type", TypeName("$$", taken))
+
+	taken = map[string]bool{}
+	assert.Equal(t, "vatNumber", FieldName("vat-number", taken))
+	assert.Equal(t, "field2fa", FieldName("2fa", taken))
+	assert.Equal(t, "caf", FieldName("café", taken))
+	assert.Equal(t, "type", FieldName("type", taken))
+
+	taken = map[string]bool{}
+	assert.Equal(t, "BANK_TRANSFER", MemberName("bank-transfer", taken))
+	assert.Equal(t, "VALUE_1", MemberName("1", taken))
+	assert.Equal(t, "MINUS_1", MemberName("-1", taken))
+	assert.Equal(t, "VALUE", MemberName("", taken))
+	assert.Equal(t, "VALUE2", MemberName("!", taken))
+
+	for plural, expected := range map[string]string{"lines": "line", "categories": "category", "addresses": "address", "boxes": "box", "status": "status", "class": "class", "data": "data"} {
+		assert.Equal(t, expected, singular(plural), plural)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	assert.Equal(t, `"a \"b\" <c>\n\u0001"`, Quote("a \"b\" <c>\n\x01"))
+}
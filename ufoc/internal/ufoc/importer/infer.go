@@ -0,0 +1,492 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/uforg/ufocontract/internal/ufoc/casing"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// Strings with at most maxEnumValues distinct values, none longer than
+// maxEnumValueLen, each seen twice on average, are enum candidates.
+const (
+	maxEnumValues   = 10
+	maxEnumValueLen = 40
+)
+
+// inferredFormats are the primitives inferred from strings, by priority.
+var inferredFormats = []ir.Primitive{ir.Datetime, ir.Date, ir.Time, ir.UUID}
+
+// kind is a set of JSON value kinds.
+type kind uint8
+
+const (
+	kindString kind = 1 << iota
+	kindBool
+	kindInt
+	kindFloat
+	kindObject
+	kindArray
+)
+
+var kindNames = []string{"string", "boolean", "integer", "number", "object", "array"}
+
+func (k kind) String() string {
+	var names []string
+	for i, name := range kindNames {
+		if k&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// shape is what the samples tell about the values at one place of the
+// documents, like a field or the items of an array.
+type shape struct {
+	kinds kind
+	null  bool
+	// present is the number of objects holding the field the shape is the
+	// value of.
+	present int
+
+	strings int
+	// values are the distinct strings, until there are too many to be an
+	// enum.
+	values     []string
+	manyValues bool
+	// formats are the inferredFormats all strings match.
+	formats []ir.Primitive
+
+	objects int
+	keys    []string
+	fields  map[string]*shape
+
+	elem *shape
+
+	// sig caches the signature.
+	sig string
+}
+
+// jsonObject is a decoded JSON object keeping the order of its keys.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (s *shape) add(v any) {
+	switch v := v.(type) {
+	case nil:
+		s.null = true
+	case bool:
+		s.kinds |= kindBool
+	case json.Number:
+		if ir.Int.CheckNumber(v.String()) == nil {
+			s.kinds |= kindInt
+		} else {
+			s.kinds |= kindFloat
+		}
+	case string:
+		s.addString(v)
+	case *jsonObject:
+		s.kinds |= kindObject
+		s.objects++
+		for _, k := range v.keys {
+			f := s.field(k)
+			f.present++
+			f.add(v.values[k])
+		}
+	case []any:
+		s.kinds |= kindArray
+		if s.elem == nil {
+			s.elem = &shape{}
+		}
+		for _, item := range v {
+			s.elem.add(item)
+		}
+	}
+}
+
+func (s *shape) addString(v string) {
+	if s.kinds&kindString == 0 {
+		s.formats = slices.Clone(inferredFormats)
+	}
+	s.kinds |= kindString
+	s.strings++
+	s.formats = slices.DeleteFunc(s.formats, func(p ir.Primitive) bool { return p.CheckString(v) != nil })
+	s.addValue(v)
+}
+
+func (s *shape) addValue(v string) {
+	switch {
+	case s.manyValues || slices.Contains(s.values, v):
+	case len(s.values) == maxEnumValues || len(v) > maxEnumValueLen:
+		s.manyValues = true
+		s.values = nil
+	default:
+		s.values = append(s.values, v)
+	}
+}
+
+func (s *shape) field(key string) *shape {
+	if s.fields == nil {
+		s.fields = map[string]*shape{}
+	}
+	f, ok := s.fields[key]
+	if !ok {
+		f = &shape{}
+		s.fields[key] = f
+		s.keys = append(s.keys, key)
+	}
+	return f
+}
+
+// merge adds what o tells to s.
+func (s *shape) merge(o *shape) {
+	if o.kinds&kindString != 0 {
+		if s.kinds&kindString == 0 {
+			s.formats = slices.Clone(o.formats)
+		} else {
+			s.formats = slices.DeleteFunc(s.formats, func(p ir.Primitive) bool { return !slices.Contains(o.formats, p) })
+		}
+		s.strings += o.strings
+		if o.manyValues {
+			s.manyValues = true
+			s.values = nil
+		}
+		for _, v := range o.values {
+			s.addValue(v)
+		}
+	}
+	s.kinds |= o.kinds
+	s.null = s.null || o.null
+	s.present += o.present
+
+	s.objects += o.objects
+	for _, k := range o.keys {
+		s.field(k).merge(o.fields[k])
+	}
+	if o.elem != nil {
+		if s.elem == nil {
+			s.elem = &shape{}
+		}
+		s.elem.merge(o.elem)
+	}
+	s.sig = ""
+}
+
+// signature describes the kinds of the values of s, with the keys of objects
+// and the items of arrays, so shapes with the same signature can share a
+// type.
+func (s *shape) signature() string {
+	if s.sig != "" {
+		return s.sig
+	}
+	var b strings.Builder
+	b.WriteString(s.kinds.String())
+	if s.kinds&kindObject != 0 {
+		keys := slices.Sorted(slices.Values(s.keys))
+		b.WriteString("{")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, "%q:%s", k, s.fields[k].signature())
+		}
+		b.WriteString("}")
+	}
+	if s.elem != nil {
+		b.WriteString("[" + s.elem.signature() + "]")
+	}
+	s.sig = b.String()
+	return s.sig
+}
+
+// sharedShape is an object shape seen at several places of the samples,
+// which becomes a type.
+type sharedShape struct {
+	// hints are the keys of the places, singular for array items.
+	hints  []string
+	shapes []*shape
+	name   string
+}
+
+type inferrer struct {
+	file   *File
+	taken  map[string]bool
+	shared map[string]*sharedShape
+}
+
+// Infer infers a type named name in the namespace from sample JSON objects.
+// Fields missing from some samples are optional, and fields that are null in
+// some are nullable. Object shapes seen at several places become shared
+// types, strings with a few repeated values become enums and strings that
+// are all timestamps, dates, times or UUIDs get those types. Enums are only
+// candidates, so each comes with a warning.
+func Infer(samples [][]byte, namespace, name string) (*File, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("no samples")
+	}
+	root := &shape{}
+	for i, data := range samples {
+		v, err := decodeSample(data)
+		if err != nil {
+			return nil, fmt.Errorf("sample %d: %w", i+1, err)
+		}
+		obj, ok := v.(*jsonObject)
+		if !ok {
+			return nil, fmt.Errorf("sample %d is not a JSON object", i+1)
+		}
+		root.add(obj)
+	}
+	if len(root.keys) == 0 {
+		return nil, errors.New("the samples have no fields")
+	}
+
+	im := &inferrer{
+		file:   &File{Namespace: namespace},
+		taken:  map[string]bool{},
+		shared: map[string]*sharedShape{},
+	}
+	rootName := TypeName(name, im.taken)
+
+	var order []*sharedShape
+	collectObjects(root, func(s *shape, hint string) {
+		sh, ok := im.shared[s.signature()]
+		if !ok {
+			sh = &sharedShape{}
+			im.shared[s.signature()] = sh
+			order = append(order, sh)
+		}
+		sh.hints = append(sh.hints, hint)
+		sh.shapes = append(sh.shapes, s)
+	})
+	var shared []*sharedShape
+	for _, sh := range order {
+		if len(sh.shapes) < 2 {
+			delete(im.shared, sh.shapes[0].signature())
+			continue
+		}
+		sh.name = TypeName(commonName(sh.hints), im.taken)
+		shared = append(shared, sh)
+	}
+
+	t := &Type{Name: rootName}
+	t.Fields, t.Warnings = im.fields(rootName, root)
+	im.file.Types = append(im.file.Types, t)
+	for _, sh := range shared {
+		merged := &shape{}
+		for _, s := range sh.shapes {
+			merged.merge(s)
+		}
+		t := &Type{Name: sh.name}
+		t.Fields, t.Warnings = im.fields(sh.name, merged)
+		im.file.Types = append(im.file.Types, t)
+	}
+	nameArrayObjects(im.file, im.taken)
+	return im.file, nil
+}
+
+// decodeSample decodes a JSON document, with its objects as jsonObject and
+// its numbers as json.Number.
+func decodeSample(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := decodeValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("unexpected data after the JSON document")
+	}
+	return v, nil
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := &jsonObject{values: map[string]any{}}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			k := key.(string)
+			if _, ok := obj.values[k]; !ok {
+				obj.keys = append(obj.keys, k)
+			}
+			obj.values[k] = v
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		_, err := dec.Token()
+		return items, err
+	default:
+		return tok, nil
+	}
+}
+
+// collectObjects calls found with the nested object shapes of s and their
+// hints.
+func collectObjects(s *shape, found func(s *shape, hint string)) {
+	var visit func(s *shape, hint string)
+	visit = func(s *shape, hint string) {
+		if s.kinds == kindObject && len(s.keys) > 0 {
+			found(s, hint)
+		}
+		collectObjects(s, found)
+	}
+	for _, k := range s.keys {
+		f := s.fields[k]
+		visit(f, k)
+		if f.elem != nil {
+			visit(f.elem, singular(k))
+		}
+	}
+}
+
+// commonName returns the words the hints end with, like Address for
+// billing_address and shipping_address, or the first hint when they have
+// none in common.
+func commonName(hints []string) string {
+	common := casing.Words(casing.Pascal(hints[0]))
+	for _, h := range hints[1:] {
+		words := casing.Words(casing.Pascal(h))
+		n := 0
+		for n < len(common) && n < len(words) && common[len(common)-1-n] == words[len(words)-1-n] {
+			n++
+		}
+		common = common[len(common)-n:]
+	}
+	if len(common) == 0 {
+		return hints[0]
+	}
+	return strings.Join(common, "")
+}
+
+// fields infers the fields of an object shape. hint names the inline
+// enums. The warnings are about the fields left out.
+func (im *inferrer) fields(hint string, s *shape) ([]*Field, []string) {
+	var fields []*Field
+	var warnings []string
+	taken := map[string]bool{}
+	for _, k := range s.keys {
+		fs := s.fields[k]
+		f := &Field{
+			WireName: &k,
+			Optional: fs.present < s.objects,
+			Nullable: fs.null,
+		}
+		ref, err := im.typeRef(fs, hint+" "+k, &f.Warnings)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("field %q is left out: %s", k, err))
+			continue
+		}
+		f.Name = FieldName(k, taken)
+		f.Type = ref
+		fields = append(fields, f)
+	}
+	return fields, warnings
+}
+
+// typeRef infers the type of the values of a shape. hint names the inline
+// enums.
+func (im *inferrer) typeRef(s *shape, hint string, warnings *[]string) (*TypeRef, error) {
+	kinds := s.kinds
+	if kinds == kindInt|kindFloat {
+		kinds = kindFloat
+	}
+
+	switch kinds {
+	case 0:
+		return nil, errors.New("it is always null")
+	case kindString:
+		return im.stringType(s, hint), nil
+	case kindBool:
+		return &TypeRef{Name: string(ir.Bool)}, nil
+	case kindInt:
+		return &TypeRef{Name: string(ir.Int)}, nil
+	case kindFloat:
+		return &TypeRef{Name: string(ir.Float)}, nil
+	case kindObject:
+		if sh, ok := im.shared[s.signature()]; ok {
+			return &TypeRef{Name: sh.name}, nil
+		}
+		if len(s.keys) == 0 {
+			return nil, errors.New("it is always an empty object")
+		}
+		fields, objectWarnings := im.fields(hint, s)
+		*warnings = append(*warnings, objectWarnings...)
+		return &TypeRef{Fields: fields}, nil
+	case kindArray:
+		if s.elem.kinds == 0 && !s.elem.null {
+			return nil, errors.New("it is always an empty array")
+		}
+		ref, err := im.typeRef(s.elem, singular(hint), warnings)
+		if err != nil {
+			return nil, fmt.Errorf("its items: %w", err)
+		}
+		if ref.Array {
+			return nil, errors.New("nested arrays are not supported")
+		}
+		if s.elem.null {
+			*warnings = append(*warnings, "array items cannot be nullable, null items are not accepted")
+		}
+		ref.Array = true
+		return ref, nil
+	default:
+		return nil, fmt.Errorf("it has values of different types: %s", kinds)
+	}
+}
+
+// stringType infers the type of strings: a primitive they all match, an
+// enum candidate or string.
+func (im *inferrer) stringType(s *shape, hint string) *TypeRef {
+	if len(s.formats) > 0 {
+		return &TypeRef{Name: string(s.formats[0])}
+	}
+	if s.manyValues || len(s.values) < 2 || s.strings < 2*len(s.values) {
+		return &TypeRef{Name: string(ir.String)}
+	}
+
+	e := &Enum{
+		Name:     TypeName(hint, im.taken),
+		Warnings: []string{fmt.Sprintf("enum candidate with the %d distinct values of %d strings, use string if other values are possible", len(s.values), s.strings)},
+	}
+	taken := map[string]bool{}
+	for _, v := range s.values {
+		m := &EnumMember{Name: MemberName(v, taken)}
+		if m.Name != v {
+			m.Value = Quote(v)
+		}
+		e.Members = append(e.Members, m)
+	}
+	im.file.Enums = append(im.file.Enums, e)
+	return &TypeRef{Name: e.Name}
+}
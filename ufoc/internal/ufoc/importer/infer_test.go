@@ -0,0 +1,181 @@
+package importer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/validate"
+)
+
+func TestInfer(t *testing.T) {
+	samples := []string{
+		`{"event_id": "7f1c2b0e-9a4d-4c1e-8f0a-2d3b4c5e6f70", "type": "order.created", "occurred_at": "2024-05-01T10:00:00Z", "order": {"id": 1, "currency": "EUR", "total": 10.5, "billing_address": {"street": "Main 1", "city": "Berlin"}, "shipping_address": {"street": "Main 1", "city": "Berlin"}, "lines": [{"sku": "A1", "qty": 2}], "tags": ["gift"]}, "note": null}`,
+		`{"event_id": "1a2b3c4d-9a4d-4c1e-8f0a-2d3b4c5e6f70", "type": "order.paid", "occurred_at": "2024-05-02T11:00:00.123+02:00", "order": {"id": 2, "currency": "USD", "total": 12, "billing_address": {"street": "Elm 2", "city": "Paris"}, "lines": [{"sku": "B2", "qty": 1}, {"sku": "C3", "qty": 4}], "tags": []}, "note": "leave at door", "retries": 1}`,
+		`{"event_id": "2a2b3c4d-9a4d-4c1e-8f0a-2d3b4c5e6f70", "type": "order.created", "occurred_at": "2024-05-03T09:00:00Z", "order": {"id": 3, "currency": "EUR", "total": 3.25, "billing_address": {"street": "Oak 3", "city": "Rome"}, "shipping_address": {"street": "Oak 3", "city": "Rome"}, "lines": [], "tags": ["vip", "gift"]}, "note": null, "day": "2024-05-03", "misc": {}, "mixed": 1}`,
+		`{"event_id": "3a2b3c4d-9a4d-4c1e-8f0a-2d3b4c5e6f70", "type": "order.paid", "occurred_at": "2024-05-04T09:00:00Z", "order": {"id": 4, "currency": "EUR", "total": 7, "billing_address": {"street": "Pine 4", "city": "Oslo"}, "lines": [{"sku": "A1", "qty": 1}], "tags": ["vip"]}, "note": "x", "mixed": "one", "ids": [[1]]}`,
+	}
+	var data [][]byte
+	for _, s := range samples {
+		data = append(data, []byte(s))
+	}
+
+	file, err := Infer(data, "Orders", "OrderEvent")
+	require.NoError(t, err)
+	src := Format(file)
+	assert.Equal(t, `version 1
+
+namespace Orders {
+  option wire.case = "snake"
+
+  // WARNING: field "misc" is left out: it is always an empty object
+  // WARNING: field "mixed" is left out: it has values of different types: string and integer
+  // WARNING: field "ids" is left out: nested arrays are not supported
+  type OrderEvent {
+    eventId: uuid
+    type: OrderEventType
+    occurredAt: datetime
+    order: {
+      id: int
+      currency: OrderEventOrderCurrency
+      total: float
+      billingAddress: Address
+      shippingAddress?: Address
+      lines: OrderEventOrderLine[]
+      tags: OrderEventOrderTag[]
+    }
+    note: string | null
+    retries?: int
+    day?: date
+  }
+
+  type OrderEventOrderLine {
+    sku: string
+    qty: int
+  }
+
+  type Address {
+    street: string
+    city: string
+  }
+
+  // WARNING: enum candidate with the 2 distinct values of 4 strings, use string if other values are possible
+  enum OrderEventType {
+    ORDER_CREATED = "order.created"
+    ORDER_PAID = "order.paid"
+  }
+
+  // WARNING: enum candidate with the 2 distinct values of 4 strings, use string if other values are possible
+  enum OrderEventOrderCurrency {
+    EUR
+    USD
+  }
+
+  // WARNING: enum candidate with the 2 distinct values of 4 strings, use string if other values are possible
+  enum OrderEventOrderTag {
+    GIFT = "gift"
+    VIP = "vip"
+  }
+}
+`, string(src))
+	checkSource(t, src)
+}
+
+func TestInferRoundTrip(t *testing.T) {
+	samples := [][]byte{
+		[]byte(`{"": "blank", "userId": 1, "created_at": "2024-05-01T10:00:00Z", "items": [{"Sku": "A1"}], "meta": {"x-trace": "a"}, "note": null}`),
+		[]byte(`{"": "other", "userId": 2, "created_at": "2024-05-02T10:00:00Z", "items": [], "meta": {"x-trace": "b"}, "note": "n", "extra": true}`),
+	}
+
+	file, err := Infer(samples, "Events", "Event")
+	require.NoError(t, err)
+	src := Format(file)
+	assert.Contains(t, string(src), "@json.name(\"\")\n    field: string\n")
+	checkSource(t, src)
+
+	contract, err := validate.LoadBytes(filepath.Join(t.TempDir(), "inferred.ufoc"), src)
+	require.NoError(t, err)
+	v, err := contract.Validator("Events.Event")
+	require.NoError(t, err)
+	for _, sample := range samples {
+		violations, err := v.Validate(sample)
+		require.NoError(t, err)
+		assert.Empty(t, violations, string(sample))
+	}
+}
+
+func TestInferSharedTypes(t *testing.T) {
+	// The addresses are named after the words their keys end with. The
+	// office address has a floor in one sample only, which makes it a
+	// different shape.
+	file, err := Infer([][]byte{
+		[]byte(`{"home_address": {"city": "Rome"}, "office": {"city": "Oslo", "floor": 2}, "past_addresses": [{"city": "Bern"}]}`),
+		[]byte(`{"home_address": {"city": "Paris"}, "office": {"city": "Lyon"}, "past_addresses": []}`),
+	}, "People", "Person")
+	require.NoError(t, err)
+	src := Format(file)
+	assert.Equal(t, `version 1
+
+namespace People {
+  option wire.case = "snake"
+
+  type Person {
+    homeAddress: Address
+    office: {
+      city: string
+      floor?: int
+    }
+    pastAddresses: Address[]
+  }
+
+  type Address {
+    city: string
+  }
+}
+`, string(src))
+	checkSource(t, src)
+}
+
+func TestInferEnumCandidates(t *testing.T) {
+	var data [][]byte
+	for i := range 30 {
+		data = append(data, []byte(`{"level": "`+[]string{"low", "high"}[i%2]+`", "id": "`+strings.Repeat("x", i+1)+`"}`))
+	}
+	file, err := Infer(data, "Logs", "Entry")
+	require.NoError(t, err)
+	require.Len(t, file.Enums, 1)
+	assert.Equal(t, "EntryLevel", file.Enums[0].Name)
+	assert.Equal(t, "string", file.Types[0].Fields[1].Type.Name)
+
+	// Values seen once are not enough to guess a closed set.
+	file, err = Infer([][]byte{[]byte(`{"name": "Ann"}`), []byte(`{"name": "Bob"}`)}, "People", "Person")
+	require.NoError(t, err)
+	assert.Empty(t, file.Enums)
+}
+
+func TestInferErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []string
+		expected string
+	}{
+		{"no samples", nil, "no samples"},
+		{"not an object", []string{`{"a": 1}`, `[1]`}, "sample 2 is not a JSON object"},
+		{"invalid JSON", []string{`{"a": }`}, "sample 1: missing value after object key"},
+		{"trailing data", []string{`{} {}`}, "sample 1: unexpected data after the JSON document"},
+		{"no fields", []string{`{}`}, "the samples have no fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data [][]byte
+			for _, s := range tt.samples {
+				data = append(data, []byte(s))
+			}
+			_, err := Infer(data, "Orders", "Order")
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
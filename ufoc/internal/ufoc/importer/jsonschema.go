@@ -0,0 +1,464 @@
+package importer
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+)
+
+// stringFormats maps the JSON Schema string formats to primitives.
+var stringFormats = map[string]ir.Primitive{
+	"date-time": ir.Datetime,
+	"date":      ir.Date,
+	"time":      ir.Time,
+	"duration":  ir.Duration,
+	"uuid":      ir.UUID,
+}
+
+// constraintKeywords are the validation keywords without an equivalent in the
+// DSL, reported as warnings.
+var constraintKeywords = []string{
+	"minLength", "maxLength", "pattern", "minimum", "maximum", "exclusiveMinimum",
+	"exclusiveMaximum", "multipleOf", "minItems", "maxItems", "uniqueItems",
+	"minProperties", "maxProperties",
+}
+
+// definition is an entry of $defs or definitions.
+type definition struct {
+	schema *schema
+	// name is the name of the imported type or enum, empty for definitions
+	// that are neither, whose uses are inlined.
+	name string
+	// enum is the imported enum of enum definitions, whose values make their
+	// uses nullable when they include null.
+	enum     *Enum
+	nullable bool
+	// failed is set when the definition could not be imported.
+	failed error
+}
+
+type jsonSchemaImporter struct {
+	file  *File
+	taken map[string]bool
+	// defs maps the references of the definitions, e.g. #/$defs/Money, to
+	// them.
+	defs map[string]*definition
+	// inlining holds the references of the definitions being inlined, to
+	// detect cycles.
+	inlining map[string]bool
+	// defaults are the fields with a default keyword, imported once every
+	// enum is known.
+	defaults []fieldDefault
+}
+
+type fieldDefault struct {
+	field *Field
+	raw   json.RawMessage
+}
+
+// JSONSchema imports a JSON Schema into the namespace. Definitions ($defs or
+// definitions) become types and enums, and so does the root schema when it
+// describes an object. The root type is named rootName or, when it is empty,
+// after the title of the schema.
+func JSONSchema(data []byte, namespace, rootName string) (*File, error) {
+	root, err := parseSchema(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.boolean != nil {
+		return nil, errors.New("the schema is a boolean schema, which has no types")
+	}
+
+	im := &jsonSchemaImporter{
+		file:     &File{Namespace: namespace},
+		taken:    map[string]bool{},
+		defs:     map[string]*definition{},
+		inlining: map[string]bool{},
+	}
+
+	type entry struct {
+		ref string
+		def *definition
+	}
+	var entries []entry
+	for _, key := range []string{"$defs", "definitions"} {
+		defs, err := root.entries(key)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range defs {
+			ref := "#/" + key + "/" + escapePointer(d.name)
+			def := &definition{schema: d.schema}
+			switch {
+			case d.schema.has("enum"):
+				def.enum, def.nullable, def.failed = enumFromValues(TypeName(d.name, im.taken), d.schema.rawList("enum"))
+				if def.failed != nil {
+					im.file.Warnings = append(im.file.Warnings, fmt.Sprintf("definition %q is left out: %s", ref, def.failed))
+					break
+				}
+				def.name = def.enum.Name
+				def.enum.Doc = d.schema.str("description")
+			case isObject(d.schema):
+				def.name = TypeName(d.name, im.taken)
+			}
+			im.defs[ref] = def
+			entries = append(entries, entry{ref: ref, def: def})
+		}
+	}
+
+	if isObject(root) {
+		if rootName == "" {
+			rootName = cmp.Or(root.str("title"), "Root")
+		}
+		t := &Type{Name: TypeName(rootName, im.taken), Doc: root.str("description")}
+		im.objectType(t, root)
+		im.file.Types = append(im.file.Types, t)
+	}
+	for _, e := range entries {
+		switch {
+		case e.def.enum != nil:
+			im.file.Enums = append(im.file.Enums, e.def.enum)
+		case e.def.name != "":
+			t := &Type{Name: e.def.name, Doc: e.def.schema.str("description")}
+			im.objectType(t, e.def.schema)
+			im.file.Types = append(im.file.Types, t)
+		case e.def.failed == nil:
+			im.file.Warnings = append(im.file.Warnings, fmt.Sprintf("definition %q is neither an object nor an enum, its uses are inlined", e.ref))
+		}
+	}
+
+	if len(im.file.Types) == 0 && len(im.file.Enums) == 0 {
+		return nil, errors.New("the schema defines no object or enum")
+	}
+	for _, d := range im.defaults {
+		if v := im.defaultValue(d.field.Type, d.raw); v != "" {
+			d.field.Default = v
+			continue
+		}
+		d.field.Warnings = append(d.field.Warnings, fmt.Sprintf("default %s is left out", d.raw))
+	}
+	nameArrayObjects(im.file, im.taken)
+	return im.file, nil
+}
+
+// isObject reports whether a schema describes an object with properties,
+// possibly composed with allOf.
+func isObject(s *schema) bool {
+	types := s.types()
+	return s.has("properties") || s.has("allOf") && (len(types) == 0 || slices.Contains(types, "object"))
+}
+
+// objectType imports the properties of an object schema into a named type,
+// turning the references of allOf into spreads.
+func (im *jsonSchemaImporter) objectType(t *Type, s *schema) {
+	for _, part := range s.list("allOf") {
+		ref := part.str("$ref")
+		def, ok := im.defs[ref]
+		switch {
+		case ref != "" && ok && def.name != "" && !def.schema.has("enum"):
+			t.Spreads = append(t.Spreads, def.name)
+		case isObject(part):
+			fields, warnings := im.fields(t.Name, part)
+			t.Fields = append(t.Fields, fields...)
+			t.Warnings = append(t.Warnings, warnings...)
+		default:
+			t.Warnings = append(t.Warnings, "an allOf entry that is not an object is left out")
+		}
+	}
+	fields, warnings := im.fields(t.Name, s)
+	t.Fields = append(t.Fields, fields...)
+	t.Warnings = append(t.Warnings, warnings...)
+}
+
+// fields imports the properties of an object schema. owner is the name of the
+// type holding them, used to name their inline enums. The warnings are about
+// the object itself and the fields left out.
+func (im *jsonSchemaImporter) fields(owner string, s *schema) ([]*Field, []string) {
+	var warnings []string
+	if extra := s.sub("additionalProperties"); extra != nil && (extra.boolean == nil || *extra.boolean) || s.has("patternProperties") {
+		warnings = append(warnings, "additional properties are not supported, only the declared properties are kept")
+	}
+
+	props, err := s.entries("properties")
+	if err != nil {
+		return nil, append(warnings, err.Error())
+	}
+	required := s.strList("required")
+	taken := map[string]bool{}
+	var fields []*Field
+	for _, p := range props {
+		f := &Field{
+			Doc:      p.schema.str("description"),
+			WireName: &p.name,
+			Optional: !slices.Contains(required, p.name),
+		}
+		ref, nullable, err := im.typeRef(p.schema, owner+" "+p.name, &f.Warnings)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("property %q is left out: %s", p.name, err))
+			continue
+		}
+		f.Name = FieldName(p.name, taken)
+		f.Type, f.Nullable = ref, nullable
+		if raw, ok := p.schema.values["default"]; ok {
+			im.defaults = append(im.defaults, fieldDefault{field: f, raw: raw})
+		}
+		fields = append(fields, f)
+	}
+	return fields, warnings
+}
+
+// typeRef imports the type of a schema. hint names the enums declared inline.
+// Information that is lost is added to warnings, while schemas that cannot be
+// represented at all return an error.
+func (im *jsonSchemaImporter) typeRef(s *schema, hint string, warnings *[]string) (*TypeRef, bool, error) {
+	if s.boolean != nil {
+		return nil, false, errors.New("boolean schemas are not supported")
+	}
+
+	if ref := s.str("$ref"); ref != "" {
+		def, ok := im.defs[ref]
+		switch {
+		case !ok:
+			return nil, false, fmt.Errorf("reference %q cannot be resolved", ref)
+		case def.failed != nil:
+			return nil, false, fmt.Errorf("referenced definition %q could not be imported", ref)
+		case def.name != "":
+			return &TypeRef{Name: def.name}, def.nullable, nil
+		case im.inlining[ref]:
+			return nil, false, fmt.Errorf("definition %q refers to itself", ref)
+		}
+		im.inlining[ref] = true
+		defer delete(im.inlining, ref)
+		return im.typeRef(def.schema, hint, warnings)
+	}
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if !s.has(key) {
+			continue
+		}
+		var alternatives []*schema
+		nullable := false
+		for _, alt := range s.list(key) {
+			if slices.Equal(alt.types(), []string{"null"}) {
+				nullable = true
+				continue
+			}
+			alternatives = append(alternatives, alt)
+		}
+		if len(alternatives) != 1 {
+			return nil, false, fmt.Errorf("%s with several alternatives is not supported", key)
+		}
+		ref, altNullable, err := im.typeRef(alternatives[0], hint, warnings)
+		return ref, nullable || altNullable, err
+	}
+	if all := s.list("allOf"); len(all) == 1 && !s.has("properties") {
+		return im.typeRef(all[0], hint, warnings)
+	}
+
+	if s.has("enum") || s.has("const") {
+		values := s.rawList("enum")
+		if s.has("const") {
+			values = []json.RawMessage{s.values["const"]}
+		}
+		e, nullable, err := enumFromValues(TypeName(hint, im.taken), values)
+		if err != nil {
+			return nil, false, err
+		}
+		e.Doc = s.str("description")
+		im.file.Enums = append(im.file.Enums, e)
+		return &TypeRef{Name: e.Name}, nullable, nil
+	}
+
+	var types []string
+	nullable := false
+	for _, t := range s.types() {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		types = append(types, t)
+	}
+	switch {
+	case len(types) > 1:
+		return nil, false, fmt.Errorf("union types are not supported")
+	case len(types) == 0 && isObject(s):
+		types = []string{"object"}
+	case len(types) == 0 && s.has("items"):
+		types = []string{"array"}
+	case len(types) == 0:
+		return nil, false, errors.New("schemas without a type are not supported")
+	}
+	constraintWarnings(s, warnings)
+
+	switch types[0] {
+	case "object":
+		if !isObject(s) {
+			return nil, false, errors.New("objects without properties are not supported")
+		}
+		fields, objectWarnings := im.fields(hint, s)
+		*warnings = append(*warnings, objectWarnings...)
+		if s.has("allOf") {
+			*warnings = append(*warnings, "allOf is only supported in definitions, its entries are left out")
+		}
+		return &TypeRef{Fields: fields}, nullable, nil
+	case "array":
+		items := s.sub("items")
+		if items == nil || s.has("prefixItems") {
+			return nil, false, errors.New("arrays without a single items schema are not supported")
+		}
+		ref, itemNullable, err := im.typeRef(items, hint+" item", warnings)
+		if err != nil {
+			return nil, false, err
+		}
+		if ref.Array {
+			return nil, false, errors.New("nested arrays are not supported")
+		}
+		if itemNullable {
+			*warnings = append(*warnings, "array items cannot be nullable, null items are not accepted")
+		}
+		ref.Array = true
+		return ref, nullable, nil
+	case "string":
+		if s.str("contentEncoding") == "base64" {
+			return &TypeRef{Name: string(ir.Bytes)}, nullable, nil
+		}
+		format := s.str("format")
+		if p, ok := stringFormats[format]; ok {
+			return &TypeRef{Name: string(p)}, nullable, nil
+		}
+		if format != "" {
+			*warnings = append(*warnings, fmt.Sprintf("format %q is not supported, the field is a plain string", format))
+		}
+		return &TypeRef{Name: string(ir.String)}, nullable, nil
+	case "integer":
+		if s.str("format") == "int32" {
+			return &TypeRef{Name: string(ir.Int32)}, nullable, nil
+		}
+		return &TypeRef{Name: string(ir.Int)}, nullable, nil
+	case "number":
+		if s.str("format") == "float" {
+			return &TypeRef{Name: string(ir.Float32)}, nullable, nil
+		}
+		return &TypeRef{Name: string(ir.Float)}, nullable, nil
+	case "boolean":
+		return &TypeRef{Name: string(ir.Bool)}, nullable, nil
+	default:
+		return nil, false, fmt.Errorf("unknown type %q", types[0])
+	}
+}
+
+// constraintWarnings adds a warning listing the validation keywords of s the
+// DSL cannot represent.
+func constraintWarnings(s *schema, warnings *[]string) {
+	var lost []string
+	for _, key := range constraintKeywords {
+		if raw, ok := s.values[key]; ok {
+			lost = append(lost, key+" "+string(raw))
+		}
+	}
+	if len(lost) > 0 {
+		*warnings = append(*warnings, "constraints are not supported: "+strings.Join(lost, ", "))
+	}
+}
+
+// enumFromValues builds an enum from the values of an enum keyword, which
+// must be all strings or all integers. A null value makes the uses of the
+// enum nullable.
+func enumFromValues(name string, values []json.RawMessage) (*Enum, bool, error) {
+	e := &Enum{Name: name}
+	taken := map[string]bool{}
+	nullable := false
+	kinds := map[string]bool{}
+	for _, raw := range values {
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, false, err
+		}
+		switch v := v.(type) {
+		case nil:
+			nullable = true
+		case string:
+			kinds["string"] = true
+			m := &EnumMember{Name: MemberName(v, taken)}
+			if m.Name != v {
+				m.Value = Quote(v)
+			}
+			e.Members = append(e.Members, m)
+		case float64:
+			n := string(raw)
+			if _, err := strconv.ParseInt(n, 10, 64); err != nil {
+				return nil, false, fmt.Errorf("enum value %s is not an integer", n)
+			}
+			kinds["int"] = true
+			e.Base = string(ir.Int)
+			e.Members = append(e.Members, &EnumMember{Name: MemberName(n, taken), Value: n})
+		default:
+			return nil, false, fmt.Errorf("enum value %s is not a string or an integer", raw)
+		}
+	}
+	switch {
+	case len(kinds) > 1:
+		return nil, false, errors.New("enums mixing strings and integers are not supported")
+	case len(e.Members) == 0:
+		return nil, false, errors.New("enums without values are not supported")
+	}
+	return e, nullable, nil
+}
+
+// defaultValue returns the default value of a field as .ufoc source, empty
+// when it cannot be imported. Only defaults of primitive and enum types are
+// imported, which are literals in both languages.
+func (im *jsonSchemaImporter) defaultValue(ref *TypeRef, raw json.RawMessage) string {
+	if ref.Array || ref.Name == "" {
+		return ""
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return ""
+	}
+
+	if p, ok := ir.LookupPrimitive(ref.Name); ok {
+		switch v := v.(type) {
+		case string:
+			if p.Textual() && p.CheckString(v) == nil {
+				return Quote(v)
+			}
+		case float64:
+			if p.Numeric() && !strings.ContainsAny(string(raw), "eE") && p.CheckNumber(string(raw)) == nil {
+				return string(raw)
+			}
+		case bool:
+			if p == ir.Bool {
+				return strconv.FormatBool(v)
+			}
+		}
+		return ""
+	}
+
+	for _, e := range im.file.Enums {
+		if e.Name != ref.Name {
+			continue
+		}
+		for _, m := range e.Members {
+			var value any = m.Name
+			if m.Value != "" {
+				_ = json.Unmarshal([]byte(m.Value), &value)
+			}
+			if value == v {
+				return m.Name
+			}
+		}
+	}
+	return ""
+}
+
+// escapePointer escapes a name for a JSON pointer.
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Invoice",
+  "description": "An invoice sent to a customer.",
+  "type": "object",
+  "required": ["invoice_id", "status", "lines", "issued_at"],
+  "properties": {
+    "invoice_id": {"type": "string", "format": "uuid"},
+    "status": {"$ref": "#/$defs/InvoiceStatus", "default": "draft"},
+    "customer": {"$ref": "#/$defs/Customer"},
+    "lines": {"type": "array", "items": {"$ref": "#/$defs/Line"}, "minItems": 1},
+    "issued_at": {"type": "string", "format": "date-time"},
+    "notes": {"type": ["string", "null"], "maxLength": 500, "description": "Free \"text\" notes."},
+    "metadata": {"type": "object", "additionalProperties": {"type": "string"}},
+    "payment": {"type": "object", "properties": {"method": {"enum": ["card", "bank-transfer"]}, "paid_at": {"type": "string", "format": "date-time"}}, "required": ["method"]},
+    "matrix": {"type": "array", "items": {"type": "array", "items": {"type": "number"}}},
+    "currency": {"$ref": "#/$defs/Currency"},
+    "priority": {"enum": [1, 2, 3], "default": 2},
+    "email": {"type": "string", "format": "email"}
+  },
+  "$defs": {
+    "InvoiceStatus": {"enum": ["draft", "sent", "paid"], "description": "Lifecycle of an invoice."},
+    "Currency": {"type": "string", "pattern": "^[A-Z]{3}$"},
+    "Base": {"type": "object", "properties": {"created_by": {"type": "string"}}},
+    "Customer": {"allOf": [{"$ref": "#/$defs/Base"}, {"properties": {"name": {"type": "string"}, "vat-number": {"type": "string"}}, "required": ["name"]}]},
+    "Line": {"type": "object", "properties": {"sku": {"type": "string"}, "quantity": {"type": "integer", "format": "int32", "minimum": 1}, "unit_price": {"type": "string", "contentEncoding": "base64"}}, "required": ["sku", "quantity"], "additionalProperties": false}
+  }
+}
+`
+
+	file, err := JSONSchema([]byte(schema), "Billing", "")
+	require.NoError(t, err)
+	src := Format(file)
+	assert.Equal(t, `version 1
+
+// WARNING: definition "#/$defs/Currency" is neither an object nor an enum, its uses are inlined
+
+namespace Billing {
+  option wire.case = "snake"
+
+  // WARNING: property "metadata" is left out: objects without properties are not supported
+  // WARNING: property "matrix" is left out: nested arrays are not supported
+  // WARNING: field "lines": constraints are not supported: minItems 1
+  // WARNING: field "notes": constraints are not supported: maxLength 500
+  // WARNING: field "currency": constraints are not supported: pattern "^[A-Z]{3}$"
+  // WARNING: field "email": format "email" is not supported, the field is a plain string
+  """ An invoice sent to a customer. """
+  type Invoice {
+    invoiceId: uuid
+    status: InvoiceStatus = DRAFT
+    customer?: Customer
+    lines: Line[]
+    issuedAt: datetime
+    """
+    Free "text" notes.
+    """
+    notes?: string | null
+    payment?: {
+      method: InvoicePaymentMethod
+      paidAt?: datetime
+    }
+    currency?: string
+    priority?: InvoicePriority = VALUE_2
+    email?: string
+  }
+
+  type Base {
+    createdBy?: string
+  }
+
+  type Customer {
+    ...Base
+    name: string
+    @json.name("vat-number")
+    vatNumber?: string
+  }
+
+  // WARNING: field "quantity": constraints are not supported: minimum 1
+  type Line {
+    sku: string
+    quantity: int32
+    unitPrice?: bytes
+  }
+
+  enum InvoicePaymentMethod {
+    CARD = "card"
+    BANK_TRANSFER = "bank-transfer"
+  }
+
+  enum InvoicePriority: int {
+    VALUE_1 = 1
+    VALUE_2 = 2
+    VALUE_3 = 3
+  }
+
+  """ Lifecycle of an invoice. """
+  enum InvoiceStatus {
+    DRAFT = "draft"
+    SENT = "sent"
+    PAID = "paid"
+  }
+}
+`, string(src))
+	assert.Equal(t, 8, file.WarningCount())
+	checkSource(t, src)
+}
+
+func TestJSONSchemaDefinitions(t *testing.T) {
+	schema := `{
+		"definitions": {
+			"Status": {"enum": ["open", "closed", null]},
+			"Mixed": {"enum": ["a", 1]},
+			"Node": {
+				"type": "object",
+				"properties": {
+					"status": {"$ref": "#/definitions/Status"},
+					"mixed": {"$ref": "#/definitions/Mixed"},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/Node"}},
+					"label": {"$ref": "#/definitions/Label"},
+					"size": {"oneOf": [{"type": "integer"}, {"type": "null"}]},
+					"value": {"anyOf": [{"type": "integer"}, {"type": "string"}]},
+					"kind": {"const": "node"},
+					"tags": {"type": "array", "items": {"type": ["string", "null"]}},
+					"edges": {"type": "array", "items": {"properties": {
+						"to": {"$ref": "#/definitions/Node"},
+						"weights": {"type": "array", "items": {"properties": {"value": {"type": "number"}}}}
+					}}},
+					"any": {}
+				},
+				"required": ["status", "kind"]
+			},
+			"Label": {"$ref": "#/definitions/Label"}
+		}
+	}`
+
+	file, err := JSONSchema([]byte(schema), "Graph", "ignored")
+	require.NoError(t, err)
+	src := Format(file)
+	assert.Equal(t, `version 1
+
+// WARNING: definition "#/definitions/Mixed" is left out: enums mixing strings and integers are not supported
+// WARNING: definition "#/definitions/Label" is neither an object nor an enum, its uses are inlined
+
+namespace Graph {
+  // WARNING: property "mixed" is left out: referenced definition "#/definitions/Mixed" could not be imported
+  // WARNING: property "label" is left out: definition "#/definitions/Label" refers to itself
+  // WARNING: property "value" is left out: anyOf with several alternatives is not supported
+  // WARNING: property "any" is left out: schemas without a type are not supported
+  // WARNING: field "tags": array items cannot be nullable, null items are not accepted
+  type Node {
+    status: Status | null
+    children?: Node[]
+    size?: int | null
+    kind: NodeKind
+    tags?: string[]
+    edges?: NodeEdge[]
+  }
+
+  type NodeEdge {
+    to?: Node
+    weights?: NodeEdgeWeight[]
+  }
+
+  type NodeEdgeWeight {
+    value?: float
+  }
+
+  enum Status {
+    OPEN = "open"
+    CLOSED = "closed"
+  }
+
+  enum NodeKind {
+    NODE = "node"
+  }
+}
+`, string(src))
+	checkSource(t, src)
+}
+
+func TestJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{"not an object", `[]`, "expected a JSON object"},
+		{"boolean", `true`, "the schema is a boolean schema, which has no types"},
+		{"no types", `{"type": "string"}`, "the schema defines no object or enum"},
+		{"invalid definitions", `{"$defs": []}`, "$defs: expected a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := JSONSchema([]byte(tt.schema), "Billing", "")
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
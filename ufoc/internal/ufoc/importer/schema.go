@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// schema is a JSON Schema, decoded keeping the order of its keywords, so
+// properties and definitions are imported in source order.
+type schema struct {
+	// boolean is set for the true and false schemas, which have no keywords.
+	boolean *bool
+	keys    []string
+	values  map[string]json.RawMessage
+}
+
+func parseSchema(data []byte) (*schema, error) {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		return &schema{boolean: &b}, nil
+	}
+
+	keys, values, err := decodeObject(data)
+	if err != nil {
+		return nil, err
+	}
+	return &schema{keys: keys, values: values}, nil
+}
+
+// decodeObject decodes a JSON object into its keys, in order, and values.
+func decodeObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, errors.New("expected a JSON object")
+	}
+
+	var keys []string
+	values := map[string]json.RawMessage{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("unexpected data after the JSON object")
+	}
+	return keys, values, nil
+}
+
+func (s *schema) has(key string) bool {
+	_, ok := s.values[key]
+	return ok
+}
+
+func (s *schema) str(key string) string {
+	var v string
+	_ = json.Unmarshal(s.values[key], &v)
+	return v
+}
+
+// types returns the type keyword, which is a name or an array of names.
+func (s *schema) types() []string {
+	raw, ok := s.values["type"]
+	if !ok {
+		return nil
+	}
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		return []string{name}
+	}
+	var names []string
+	_ = json.Unmarshal(raw, &names)
+	return names
+}
+
+func (s *schema) strList(key string) []string {
+	var v []string
+	_ = json.Unmarshal(s.values[key], &v)
+	return v
+}
+
+func (s *schema) rawList(key string) []json.RawMessage {
+	var v []json.RawMessage
+	_ = json.Unmarshal(s.values[key], &v)
+	return v
+}
+
+// sub returns the schema of a keyword taking a schema, nil when it is not
+// set or is not a schema.
+func (s *schema) sub(key string) *schema {
+	raw, ok := s.values[key]
+	if !ok {
+		return nil
+	}
+	sub, err := parseSchema(raw)
+	if err != nil {
+		return nil
+	}
+	return sub
+}
+
+// list returns the schemas of a keyword taking an array of schemas.
+func (s *schema) list(key string) []*schema {
+	var out []*schema
+	for _, raw := range s.rawList(key) {
+		if sub, err := parseSchema(raw); err == nil {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+// namedSchema is an entry of a keyword mapping names to schemas, like
+// properties or $defs.
+type namedSchema struct {
+	name   string
+	schema *schema
+}
+
+// entries returns the entries of a keyword mapping names to schemas, in
+// source order.
+func (s *schema) entries(key string) ([]namedSchema, error) {
+	raw, ok := s.values[key]
+	if !ok {
+		return nil, nil
+	}
+	keys, values, err := decodeObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	out := make([]namedSchema, 0, len(keys))
+	for _, k := range keys {
+		sub, err := parseSchema(values[k])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", key, k, err)
+		}
+		out = append(out, namedSchema{name: k, schema: sub})
+	}
+	return out, nil
+}
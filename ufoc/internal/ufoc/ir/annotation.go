@@ -0,0 +1,56 @@
+package ir
+
+import "github.com/alecthomas/participle/v2/lexer"
+
+// Built-in annotations, namespaced by the target they apply to. The analyzer
+// validates them, while annotations of other namespaces are kept as written
+// for plugins.
+const (
+	AnnotationGoName     = "go.name"
+	AnnotationGoType     = "go.type"
+	AnnotationTSName     = "ts.name"
+	AnnotationTSType     = "ts.type"
+	AnnotationPythonName = "python.name"
+	AnnotationJSONName   = "json.name"
+	AnnotationJSONFlags  = "json.flags"
+)
+
+// Built-in annotations of enums and enum members, which apply to every
+// target.
+const (
+	AnnotationAlias    = "alias"
+	AnnotationFallback = "fallback"
+	AnnotationUnknown  = "unknown"
+)
+
+// Annotation is an @name(args) annotation, e.g. @go.name("ID").
+type Annotation struct {
+	Pos  lexer.Position
+	Name string
+	Args []*Value
+}
+
+// Annotations are the annotations of a definition, field or enum member, in
+// source order.
+type Annotations []*Annotation
+
+// Get returns the first annotation with the given name, nil when there is
+// none.
+func (as Annotations) Get(name string) *Annotation {
+	for _, a := range as {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// String returns the string argument of the first annotation with the given
+// name, which is how every built-in annotation takes its value.
+func (as Annotations) String(name string) (string, bool) {
+	a := as.Get(name)
+	if a == nil || len(a.Args) != 1 || a.Args[0].Literal().String == nil {
+		return "", false
+	}
+	return *a.Args[0].Literal().String, true
+}
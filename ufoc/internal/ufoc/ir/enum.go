@@ -0,0 +1,183 @@
+package ir
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+)
+
+// FlagsBase is the base type of bit-flag enums, whose values are sets of
+// members encoded as bitmasks.
+const FlagsBase = "flags"
+
+// MaxFlags is the number of members a flags enum can have, so every mask fits
+// in a signed 64-bit integer.
+const MaxFlags = 63
+
+// Unknown-value policies of enums, set with the @unknown annotation. They
+// control what decoders do with values that match no member, e.g. members
+// added by a newer version of the contract.
+const (
+	UnknownReject   = "reject"
+	UnknownFallback = "fallback"
+	UnknownPreserve = "preserve"
+)
+
+// Aliases returns the extra wire values of the member, set with the @alias
+// annotation. Decoders accept them as the member, encoders always write
+// WireValue.
+func (m *EnumMember) Aliases() []string {
+	var aliases []string
+	for _, a := range m.Annotations {
+		if a.Name != AnnotationAlias {
+			continue
+		}
+		for _, arg := range a.Args {
+			v := arg.Literal()
+			switch {
+			case v.String != nil:
+				aliases = append(aliases, *v.String)
+			case v.Number != nil:
+				aliases = append(aliases, *v.Number)
+			}
+		}
+	}
+	return aliases
+}
+
+// Fallback reports whether the member has the @fallback annotation.
+func (m *EnumMember) Fallback() bool {
+	return m.Annotations.Get(AnnotationFallback) != nil
+}
+
+// FallbackMember returns the member unknown values are decoded as, nil when
+// no member has the @fallback annotation.
+func (e *Enum) FallbackMember() *EnumMember {
+	for _, m := range e.Members {
+		if m.Fallback() {
+			return m
+		}
+	}
+	return nil
+}
+
+// UnknownPolicy returns the unknown-value policy of the enum: its @unknown
+// annotation, UnknownFallback when a member has the @fallback annotation or
+// UnknownReject.
+func (e *Enum) UnknownPolicy() string {
+	if v, ok := e.Annotations.String(AnnotationUnknown); ok {
+		return v
+	}
+	if e.FallbackMember() != nil {
+		return UnknownFallback
+	}
+	return UnknownReject
+}
+
+// Decode returns the member a wire value is decoded as, matching the member
+// values and their aliases. Unknown values are decoded following the unknown
+// policy: as the fallback member, as nil for UnknownPreserve, which keeps
+// the raw value, or as an error for UnknownReject.
+func (e *Enum) Decode(wire string) (*EnumMember, error) {
+	for _, m := range e.Members {
+		if m.WireValue() == wire {
+			return m, nil
+		}
+	}
+	for _, m := range e.Members {
+		for _, alias := range m.Aliases() {
+			if alias == wire {
+				return m, nil
+			}
+		}
+	}
+
+	switch e.UnknownPolicy() {
+	case UnknownFallback:
+		if m := e.FallbackMember(); m != nil {
+			return m, nil
+		}
+	case UnknownPreserve:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown value %q of enum %q", wire, e.Name)
+}
+
+// Flags reports whether the enum is a flags enum.
+func (e *Enum) Flags() bool {
+	return e.BaseType == FlagsBase
+}
+
+// FlagValue returns the bit of a member of a flags enum: its explicit value
+// or, for members without one, the lowest bit not taken by an explicit value
+// or a previous member. It returns 0 when no bit is left or the explicit
+// value is not a number.
+func (e *Enum) FlagValue(m *EnumMember) uint64 {
+	var used uint64
+	for _, other := range e.Members {
+		if v, ok := explicitFlag(other); ok {
+			used |= v
+		}
+	}
+	for _, other := range e.Members {
+		v, ok := explicitFlag(other)
+		if !ok {
+			v = ^used & (used + 1)
+			if bits.TrailingZeros64(v) >= MaxFlags {
+				v = 0
+			}
+			used |= v
+		}
+		if other == m {
+			return v
+		}
+	}
+	return 0
+}
+
+func explicitFlag(m *EnumMember) (uint64, bool) {
+	if m.Value == nil {
+		return 0, false
+	}
+	v := m.Value.Literal()
+	if v.Number == nil {
+		return 0, true
+	}
+	n, err := strconv.ParseUint(*v.Number, 10, 64)
+	if err != nil {
+		return 0, true
+	}
+	return n, true
+}
+
+// FlagNames returns the names of the members set in mask, in declaration
+// order, and the bits of mask that belong to no member.
+func (e *Enum) FlagNames(mask uint64) ([]string, uint64) {
+	var names []string
+	for _, m := range e.Members {
+		if v := e.FlagValue(m); v != 0 && mask&v == v {
+			names = append(names, m.Name)
+			mask &^= v
+		}
+	}
+	return names, mask
+}
+
+// FlagMask returns the mask with the named members set.
+func (e *Enum) FlagMask(names []string) (uint64, error) {
+	var mask uint64
+	for _, name := range names {
+		found := false
+		for _, m := range e.Members {
+			if m.Name == name {
+				mask |= e.FlagValue(m)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("%q is not a member of flags enum %q", name, e.Name)
+		}
+	}
+	return mask, nil
+}
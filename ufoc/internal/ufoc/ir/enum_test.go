@@ -0,0 +1,78 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumDecode(t *testing.T) {
+	str := func(s string) *Value { return &Value{String: &s} }
+	running := &EnumMember{Name: "RUNNING", Value: str("running"), Annotations: Annotations{
+		{Name: AnnotationAlias, Args: []*Value{str("in_progress"), str("started")}},
+	}}
+	unknown := &EnumMember{Name: "UNKNOWN", Annotations: Annotations{{Name: AnnotationFallback}}}
+	e := &Enum{Name: "TaskStatus", Members: []*EnumMember{{Name: "PENDING"}, running}}
+
+	assert.Equal(t, []string{"in_progress", "started"}, running.Aliases())
+	assert.Equal(t, UnknownReject, e.UnknownPolicy())
+
+	m, err := e.Decode("PENDING")
+	require.NoError(t, err)
+	assert.Same(t, e.Members[0], m)
+	m, err = e.Decode("started")
+	require.NoError(t, err)
+	assert.Same(t, running, m)
+	_, err = e.Decode("paused")
+	assert.EqualError(t, err, `unknown value "paused" of enum "TaskStatus"`)
+
+	e.Members = append(e.Members, unknown)
+	assert.Equal(t, UnknownFallback, e.UnknownPolicy())
+	assert.Same(t, unknown, e.FallbackMember())
+	m, err = e.Decode("paused")
+	require.NoError(t, err)
+	assert.Same(t, unknown, m)
+
+	e.Members = e.Members[:2]
+	e.Annotations = Annotations{{Name: AnnotationUnknown, Args: []*Value{str(UnknownPreserve)}}}
+	m, err = e.Decode("paused")
+	require.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestEnumFlags(t *testing.T) {
+	num := func(s string) *Value { return &Value{Number: &s} }
+	e := &Enum{Name: "Permission", BaseType: FlagsBase, Members: []*EnumMember{
+		{Name: "READ"},
+		{Name: "ADMIN", Value: num("8")},
+		{Name: "WRITE"},
+		{Name: "DELETE"},
+	}}
+	require.True(t, e.Flags())
+
+	var values []uint64
+	for _, m := range e.Members {
+		values = append(values, e.FlagValue(m))
+	}
+	// Explicit bits are skipped by the automatic ones.
+	assert.Equal(t, []uint64{1, 8, 2, 4}, values)
+
+	names, rest := e.FlagNames(1 | 8 | 32)
+	assert.Equal(t, []string{"READ", "ADMIN"}, names)
+	assert.Equal(t, uint64(32), rest)
+
+	mask, err := e.FlagMask([]string{"WRITE", "DELETE"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), mask)
+	_, err = e.FlagMask([]string{"EXECUTE"})
+	assert.EqualError(t, err, `"EXECUTE" is not a member of flags enum "Permission"`)
+
+	ns := &Namespace{Name: "Auth"}
+	assert.Equal(t, FlagsInt, ns.FlagsEncoding(e))
+	ns.Options = []*Option{{Key: OptionJSONFlags, Value: FlagsNames}}
+	assert.Equal(t, FlagsNames, ns.FlagsEncoding(e))
+	s := FlagsInt
+	e.Annotations = Annotations{{Name: AnnotationJSONFlags, Args: []*Value{{String: &s}}}}
+	assert.Equal(t, FlagsInt, ns.FlagsEncoding(e))
+}
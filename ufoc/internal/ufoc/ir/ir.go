@@ -0,0 +1,435 @@
+// Package ir contains the resolved model of a contract. It is built from the
+// parser AST by the loader and is what every consumer (analyzer, generators,
+// docs) works with, so none of them has to deal with raw source syntax.
+package ir
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Schema is the resolved model of a single .ufoc file.
+type Schema struct {
+	Version int
+	Docs    []*Doc
+	// Options holds the file options, which apply to every namespace that
+	// does not set them itself.
+	Options []*Option
+	// Namespaces holds the top-level namespaces, see AllNamespaces for the
+	// nested ones.
+	Namespaces []*Namespace
+}
+
+// AllNamespaces returns the namespaces of the schema with the nested
+// namespaces following their parent, in declaration order.
+func (s *Schema) AllNamespaces() []*Namespace {
+	var out []*Namespace
+	var add func(namespaces []*Namespace)
+	add = func(namespaces []*Namespace) {
+		for _, ns := range namespaces {
+			out = append(out, ns)
+			add(ns.Namespaces)
+		}
+	}
+	add(s.Namespaces)
+	return out
+}
+
+// LookupType returns the type with the given qualified name, e.g.
+// Billing.Invoices.Invoice, and the namespace declaring it. It returns nil
+// when there is no such type.
+func (s *Schema) LookupType(name string) (*Namespace, *Type) {
+	i := strings.LastIndexByte(name, '.')
+	if i < 0 {
+		return nil, nil
+	}
+	path, typeName := name[:i], name[i+1:]
+	for _, ns := range s.AllNamespaces() {
+		if ns.Path() != path {
+			continue
+		}
+		for _, t := range ns.Types {
+			if t.Name == typeName {
+				return ns, t
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Doc is a docstring, either associated with a definition or standalone.
+type Doc struct {
+	Pos lexer.Position
+	// Raw is the docstring exactly as written in the source, delimiters included.
+	Raw string
+	// Text is the normalized documentation content (see section 8.2 of the
+	// spec). For external docstrings this is the content of the referenced file.
+	Text string
+	// Source is the absolute path of the referenced Markdown file for external
+	// docstrings, empty for inline ones.
+	Source string
+}
+
+// External reports whether the documentation was loaded from a Markdown file.
+func (d *Doc) External() bool {
+	return d != nil && d.Source != ""
+}
+
+// Deprecation marks a definition as deprecated.
+type Deprecation struct {
+	// Message is the optional deprecation message, without quotes, empty when
+	// omitted.
+	Message string
+}
+
+type Namespace struct {
+	Pos lexer.Position
+	// NamePos is the position of the name, while Pos is the position of the
+	// whole definition including its docstring.
+	NamePos lexer.Position
+	Doc     *Doc
+	// Name is the identifier of the namespace used in code. For quoted names
+	// like "billing-v2" it is derived from DisplayName.
+	Name string
+	// DisplayName is the name as written in the source, without quotes.
+	DisplayName string
+	Options     []*Option
+	Docs        []*Doc
+	Types       []*Type
+	// Instances holds the instantiations of the generic types of the namespace,
+	// in order of first use. They are created by the analyzer.
+	Instances []*Instance
+	Enums     []*Enum
+	Consts    []*Const
+	Patterns  []*Pattern
+	// Parent is the enclosing namespace, nil for top-level namespaces.
+	Parent     *Namespace
+	Namespaces []*Namespace
+	// Schema is the schema the namespace belongs to, whose file options it
+	// inherits. It is nil for namespaces built outside of the loader.
+	Schema *Schema
+}
+
+// Path returns the qualified name of the namespace, e.g. Billing.Invoices.
+func (ns *Namespace) Path() string {
+	if ns.Parent == nil {
+		return ns.Name
+	}
+	return ns.Parent.Path() + "." + ns.Name
+}
+
+// Root returns the top-level namespace of the tree the namespace belongs to,
+// which is the namespace itself for top-level namespaces.
+func (ns *Namespace) Root() *Namespace {
+	for ns.Parent != nil {
+		ns = ns.Parent
+	}
+	return ns
+}
+
+type Type struct {
+	Pos         lexer.Position
+	NamePos     lexer.Position
+	Doc         *Doc
+	Annotations Annotations
+	Deprecated  *Deprecation
+	// Internal definitions can only be used within the namespace tree they
+	// belong to and are left out of generated public APIs and docs.
+	Internal   bool
+	Name       string
+	TypeParams []*TypeParam
+	Spreads    []*Spread
+	// Fields holds the fields declared in the type body, see AllFields for the
+	// fields including the ones from spreads.
+	Fields []*Field
+}
+
+// Generic reports whether the type has type parameters.
+func (t *Type) Generic() bool {
+	return len(t.TypeParams) > 0
+}
+
+// TypeParam is a type parameter of a generic type, e.g. T in Page<T>.
+type TypeParam struct {
+	Pos  lexer.Position
+	Name string
+}
+
+// Spread is a ...BaseEntity entry in a type body, which flattens the fields
+// of the referenced type into the type.
+type Spread struct {
+	Pos  lexer.Position
+	Type *TypeRef
+}
+
+// AllFields returns the fields of the type with the fields of its spreads
+// flattened in, in declaration order.
+func (t *Type) AllFields() []*Field {
+	return t.allFields(map[*Type]bool{})
+}
+
+func (t *Type) allFields(visiting map[*Type]bool) []*Field {
+	if len(t.Spreads) == 0 {
+		return t.Fields
+	}
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var fields []*Field
+	spreads := t.Spreads
+	for _, f := range t.Fields {
+		for len(spreads) > 0 && spreads[0].Pos.Offset < f.Pos.Offset {
+			fields = append(fields, spreads[0].fields(visiting)...)
+			spreads = spreads[1:]
+		}
+		fields = append(fields, f)
+	}
+	for _, s := range spreads {
+		fields = append(fields, s.fields(visiting)...)
+	}
+	return fields
+}
+
+func (s *Spread) fields(visiting map[*Type]bool) []*Field {
+	switch {
+	case s.Type.Instance != nil:
+		return s.Type.Instance.Type.allFields(visiting)
+	case s.Type.Type != nil:
+		return s.Type.Type.allFields(visiting)
+	default:
+		return nil
+	}
+}
+
+type Field struct {
+	Pos         lexer.Position
+	NamePos     lexer.Position
+	Doc         *Doc
+	Annotations Annotations
+	Name        string
+	// Optional fields may be absent (name?: T) and nullable fields may be
+	// null (name: T | null). The two are independent.
+	Optional bool
+	Nullable bool
+	Type     *TypeRef
+	// Default is the default value of the field, nil when it has none.
+	Default *Value
+}
+
+// TriState reports whether the field can be absent, null or set, which
+// targets like Go represent with a dedicated wrapper type.
+func (f *Field) TriState() bool {
+	return f.Optional && f.Nullable
+}
+
+// TypeRef is a reference to a named type or an inline object type.
+type TypeRef struct {
+	Pos lexer.Position
+	// Name is the referenced primitive or custom type, empty for inline types.
+	Name string
+	// Fields holds the fields of an inline object type.
+	Fields []*Field
+	// Args holds the type arguments of a generic type reference, e.g. Task in
+	// Page<Task>.
+	Args  []*TypeRef
+	Array bool
+
+	// Primitive, Type, Enum and TypeParam are set by the analyzer when it
+	// resolves a named reference. Exactly one of them is set for valid
+	// references.
+	Primitive Primitive
+	Type      *Type
+	Enum      *Enum
+	TypeParam *TypeParam
+	// Instance is set by the analyzer for references to generic types whose
+	// type arguments are all concrete.
+	Instance *Instance
+}
+
+// Inline reports whether the reference is an inline object type.
+func (t *TypeRef) Inline() bool {
+	return t.Name == ""
+}
+
+// Instance is a generic type instantiated with concrete type arguments, e.g.
+// Page<Task>. Generators for targets without generics use the monomorphized
+// Type instead of the generic one.
+type Instance struct {
+	Generic *Type
+	Args    []*TypeRef
+	// Type is the generic type with its type parameters substituted by the type
+	// arguments, named after the instantiation (PageTask).
+	Type *Type
+}
+
+type Enum struct {
+	Pos         lexer.Position
+	NamePos     lexer.Position
+	Doc         *Doc
+	Annotations Annotations
+	Deprecated  *Deprecation
+	Internal    bool
+	Name        string
+	// BaseType is the explicit base type, empty when omitted. It is one of
+	// string, int and FlagsBase.
+	BaseType string
+	Members  []*EnumMember
+}
+
+type EnumMember struct {
+	Pos         lexer.Position
+	NamePos     lexer.Position
+	Doc         *Doc
+	Annotations Annotations
+	Name        string
+	Value       *Value
+}
+
+// WireValue returns the value of the member in encoded data: its explicit
+// value or, for members without one, its name. Members of flags enums are
+// encoded as bits instead, see Enum.FlagValue.
+func (m *EnumMember) WireValue() string {
+	if m.Value != nil {
+		v := m.Value.Literal()
+		switch {
+		case v.String != nil:
+			return *v.String
+		case v.Number != nil:
+			return *v.Number
+		}
+	}
+	return m.Name
+}
+
+type Const struct {
+	Pos         lexer.Position
+	NamePos     lexer.Position
+	Doc         *Doc
+	Annotations Annotations
+	Deprecated  *Deprecation
+	Internal    bool
+	Name        string
+	Type        *TypeRef
+	Value       *Value
+}
+
+type Pattern struct {
+	Pos         lexer.Position
+	NamePos     lexer.Position
+	Doc         *Doc
+	Annotations Annotations
+	Deprecated  *Deprecation
+	Internal    bool
+	Name        string
+	// Dialect is one of the Dialect constants, empty when omitted.
+	Dialect    string
+	DialectPos lexer.Position
+	// Pattern is the pattern string without quotes.
+	Pattern  string
+	Segments []*PatternSegment
+}
+
+// PatternSegment is either a literal part of a pattern or a placeholder.
+type PatternSegment struct {
+	// Pos is the position of the literal text, unset for placeholders.
+	Pos lexer.Position
+	// Literal is the unescaped literal text, empty for placeholders.
+	Literal     string
+	Placeholder *Placeholder
+}
+
+// Placeholder is a {name} or {name: Type} placeholder of a pattern.
+type Placeholder struct {
+	// Pos is the position of the name.
+	Pos  lexer.Position
+	Name string
+	// Type is the explicit type of the placeholder, nil when omitted, in which
+	// case the placeholder is a string.
+	Type *TypeRef
+}
+
+// Value is a literal value, a reference or a constant expression. Exactly one
+// of String, Number, Ident, Array, Object, Unary and Binary is set.
+type Value struct {
+	Pos lexer.Position
+	// String is the unquoted string literal.
+	String *string
+	Number *string
+	// Ident is a reference to a constant or an enum member, either qualified
+	// (TaskStatus.PENDING) or not (PENDING), or one of true, false and null.
+	Ident  *string
+	Array  *ArrayValue
+	Object *ObjectValue
+	Unary  *UnaryExpr
+	Binary *BinaryExpr
+
+	// Member is set by the analyzer when the value names an enum member.
+	Member *EnumMember
+	// Const is set by the analyzer when the value is a reference to a constant.
+	Const *Const
+	// Folded is set by the analyzer to the literal result of expressions and
+	// constant references.
+	Folded *Value
+}
+
+// Null reports whether the value is the null literal.
+func (v *Value) Null() bool {
+	return v.Ident != nil && *v.Ident == "null"
+}
+
+// Literal returns the literal the value evaluates to, which is the value
+// itself for literals. Expressions are only folded by the analyzer.
+func (v *Value) Literal() *Value {
+	if v.Folded != nil {
+		return v.Folded
+	}
+	return v
+}
+
+// UnaryExpr is a negated value, e.g. -BaseOffset.
+type UnaryExpr struct {
+	Op      string
+	Operand *Value
+}
+
+// BinaryExpr is an arithmetic operation or a string concatenation, e.g.
+// BaseBackoffMs * 32 or ErrorQueue + ".dlq".
+type BinaryExpr struct {
+	Op    string
+	Left  *Value
+	Right *Value
+}
+
+// ArrayValue is an array literal, e.g. ["eu", "us"].
+type ArrayValue struct {
+	Items []*Value
+}
+
+// ObjectValue is an object literal, e.g. { maxAttempts: 3, backoffMs: 500 }.
+type ObjectValue struct {
+	Entries []*ObjectEntry
+}
+
+// Entry returns the entry with the given key.
+func (o *ObjectValue) Entry(key string) *ObjectEntry {
+	for _, e := range o.Entries {
+		if e.Key == key {
+			return e
+		}
+	}
+	return nil
+}
+
+type ObjectEntry struct {
+	Pos   lexer.Position
+	Key   string
+	Value *Value
+
+	// Field is set by the analyzer to the field of the object type that the
+	// entry sets.
+	Field *Field
+}
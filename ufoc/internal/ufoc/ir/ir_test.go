@@ -0,0 +1,79 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypeAllFields(t *testing.T) {
+	at := func(offset int) lexer.Position { return lexer.Position{Offset: offset} }
+
+	base := &Type{
+		Name: "BaseEntity",
+		Fields: []*Field{
+			{Pos: at(10), Name: "id"},
+			{Pos: at(20), Name: "createdAt"},
+		},
+	}
+	audited := &Type{
+		Name:    "Audited",
+		Spreads: []*Spread{{Pos: at(5), Type: &TypeRef{Name: "BaseEntity", Type: base}}},
+		Fields:  []*Field{{Pos: at(30), Name: "updatedBy"}},
+	}
+	user := &Type{
+		Name: "User",
+		Spreads: []*Spread{
+			{Pos: at(50), Type: &TypeRef{Name: "Audited", Type: audited}},
+			{Pos: at(70), Type: &TypeRef{Name: "Unresolved"}},
+		},
+		Fields: []*Field{
+			{Pos: at(40), Name: "email"},
+			{Pos: at(60), Name: "name"},
+		},
+	}
+
+	var names []string
+	for _, f := range user.AllFields() {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"email", "id", "createdAt", "updatedBy", "name"}, names)
+}
+
+func TestTypeAllFieldsCycle(t *testing.T) {
+	a := &Type{Name: "A", Fields: []*Field{{Name: "a"}}}
+	b := &Type{Name: "B", Fields: []*Field{{Name: "b"}}}
+	a.Spreads = []*Spread{{Type: &TypeRef{Name: "B", Type: b}}}
+	b.Spreads = []*Spread{{Type: &TypeRef{Name: "A", Type: a}}}
+
+	var names []string
+	for _, f := range a.AllFields() {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"a", "b"}, names)
+}
+
+func TestSchemaLookupType(t *testing.T) {
+	task := &Type{Name: "Task"}
+	invoice := &Type{Name: "Invoice"}
+	tasks := &Namespace{Name: "Tasks", Types: []*Type{task}}
+	billing := &Namespace{Name: "Billing"}
+	invoices := &Namespace{Name: "Invoices", Parent: billing, Types: []*Type{invoice}}
+	billing.Namespaces = []*Namespace{invoices}
+	schema := &Schema{Namespaces: []*Namespace{tasks, billing}}
+
+	ns, typ := schema.LookupType("Tasks.Task")
+	assert.Same(t, tasks, ns)
+	assert.Same(t, task, typ)
+
+	ns, typ = schema.LookupType("Billing.Invoices.Invoice")
+	assert.Same(t, invoices, ns)
+	assert.Same(t, invoice, typ)
+
+	for _, name := range []string{"Task", "Billing.Invoice", "Tasks.Missing", "Missing.Task"} {
+		ns, typ = schema.LookupType(name)
+		assert.Nil(t, ns, name)
+		assert.Nil(t, typ, name)
+	}
+}
@@ -0,0 +1,325 @@
+package ir
+
+import (
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/casing"
+)
+
+// Known namespace option keys.
+const (
+	OptionGoPackage    = "go.package"
+	OptionTSModule     = "ts.module"
+	OptionPythonModule = "python.module"
+	OptionGoUUID       = "go.uuid"
+	OptionGoSpread     = "go.spread"
+	OptionWildcard     = "pattern.wildcard"
+	OptionWireCase     = "wire.case"
+	OptionTSCase       = "ts.case"
+	OptionPythonCase   = "python.case"
+	OptionJSONFlags    = "json.flags"
+)
+
+// Values of the go.uuid option.
+const (
+	GoUUIDString = "string"
+	GoUUIDBytes  = "bytes"
+)
+
+// Values of the go.spread option.
+const (
+	GoSpreadEmbed = "embed"
+	GoSpreadCopy  = "copy"
+)
+
+// Values of the pattern.wildcard option, the wildcard syntaxes of the
+// messaging systems patterns are subscribed with.
+const (
+	WildcardNATS  = "nats"
+	WildcardMQTT  = "mqtt"
+	WildcardRegex = "regex"
+	WildcardRedis = "redis"
+)
+
+// Values of the wire.case, ts.case and python.case options, the casing of
+// field names in encoded data and in the generated code. Kebab and Pascal are
+// only valid for wire.case.
+const (
+	CasePreserve = "preserve"
+	CaseCamel    = "camel"
+	CaseSnake    = "snake"
+	CaseKebab    = "kebab"
+	CasePascal   = "pascal"
+)
+
+// Values of the json.flags option, the JSON encoding of flags enums: the
+// integer bitmask or the array of the names of the members set.
+const (
+	FlagsInt   = "int"
+	FlagsNames = "names"
+)
+
+// Option is a namespace option, e.g. option go.package = "billingv2".
+type Option struct {
+	Pos lexer.Position
+	Key string
+	// Value is the option value without quotes.
+	Value string
+}
+
+// Option returns the value of the first option with the given key.
+func (ns *Namespace) Option(key string) (string, bool) {
+	for _, o := range ns.Options {
+		if o.Key == key {
+			return o.Value, true
+		}
+	}
+	return "", false
+}
+
+// Option returns the value of the first file option with the given key.
+func (s *Schema) Option(key string) (string, bool) {
+	for _, o := range s.Options {
+		if o.Key == key {
+			return o.Value, true
+		}
+	}
+	return "", false
+}
+
+// inheritedOption returns the value of an option of the namespace or, when it
+// is not set, of its nearest enclosing namespace that sets it, falling back to
+// the file options.
+func (ns *Namespace) inheritedOption(key string) (string, bool) {
+	root := ns.Root()
+	for ; ns != nil; ns = ns.Parent {
+		if v, ok := ns.Option(key); ok {
+			return v, true
+		}
+	}
+	if root.Schema != nil {
+		return root.Schema.Option(key)
+	}
+	return "", false
+}
+
+// GoPackage returns the name of the generated Go package: the go.package
+// option or the namespace name lowercased without separators ("billingv2").
+func (ns *Namespace) GoPackage() string {
+	if v, ok := ns.Option(OptionGoPackage); ok {
+		return v
+	}
+	return strings.ToLower(strings.Join(casing.Words(ns.Name), ""))
+}
+
+// GoPackagePath returns the directory of the generated Go package relative to
+// the output directory. Nested namespaces are sub-packages of their parent
+// ("billing/invoices").
+func (ns *Namespace) GoPackagePath() string {
+	if ns.Parent == nil {
+		return ns.GoPackage()
+	}
+	return ns.Parent.GoPackagePath() + "/" + ns.GoPackage()
+}
+
+// TSModule returns the path of the generated TypeScript module: the ts.module
+// option or the namespace name in kebab-case ("billing-v2"). Nested
+// namespaces are sub-modules of their parent ("billing-v2/invoices").
+func (ns *Namespace) TSModule() string {
+	name, ok := ns.Option(OptionTSModule)
+	if !ok {
+		name = casing.Kebab(ns.Name)
+	}
+	if ns.Parent == nil {
+		return name
+	}
+	return ns.Parent.TSModule() + "/" + name
+}
+
+// PythonModule returns the name of the generated Python module: the
+// python.module option or the namespace name in snake_case ("billing_v2").
+// Nested namespaces are sub-modules of their parent ("billing_v2.invoices").
+func (ns *Namespace) PythonModule() string {
+	name, ok := ns.Option(OptionPythonModule)
+	if !ok {
+		name = casing.Snake(ns.Name)
+	}
+	if ns.Parent == nil {
+		return name
+	}
+	return ns.Parent.PythonModule() + "." + name
+}
+
+// GoUUID returns how uuid values are represented in Go: GoUUIDString (the
+// default) or GoUUIDBytes for [16]byte. Like the other choice options, it is
+// inherited by nested namespaces.
+func (ns *Namespace) GoUUID() string {
+	if v, ok := ns.inheritedOption(OptionGoUUID); ok {
+		return v
+	}
+	return GoUUIDString
+}
+
+// GoSpread returns how spreads are generated in Go: GoSpreadEmbed (the
+// default) embeds the spread struct, GoSpreadCopy copies its fields.
+func (ns *Namespace) GoSpread() string {
+	if v, ok := ns.inheritedOption(OptionGoSpread); ok {
+		return v
+	}
+	return GoSpreadEmbed
+}
+
+// Wildcard returns the wildcard syntax used for pattern subscriptions:
+// WildcardNATS (the default), WildcardMQTT, WildcardRegex or WildcardRedis.
+func (ns *Namespace) Wildcard() string {
+	if v, ok := ns.inheritedOption(OptionWildcard); ok {
+		return v
+	}
+	return WildcardNATS
+}
+
+// WireCase returns the casing of field names in encoded data: CasePreserve
+// (the default) keeps the names as written, the other values convert them.
+func (ns *Namespace) WireCase() string {
+	if v, ok := ns.inheritedOption(OptionWireCase); ok {
+		return v
+	}
+	return CasePreserve
+}
+
+// TSCase returns the casing of field names in the generated TypeScript code,
+// CasePreserve by default.
+func (ns *Namespace) TSCase() string {
+	if v, ok := ns.inheritedOption(OptionTSCase); ok {
+		return v
+	}
+	return CasePreserve
+}
+
+// PythonCase returns the casing of field names in the generated Python code,
+// CasePreserve by default.
+func (ns *Namespace) PythonCase() string {
+	if v, ok := ns.inheritedOption(OptionPythonCase); ok {
+		return v
+	}
+	return CasePreserve
+}
+
+// WireName returns the name of a field of the namespace in encoded data (JSON
+// keys, serialization tags, JSON Schema properties and docs): its @json.name
+// annotation or the field name in the wire case of the namespace. ns is the
+// namespace declaring the field, also for fields spread into a type of another
+// namespace, so a field has the same wire name everywhere.
+func (ns *Namespace) WireName(f *Field) string {
+	if name, ok := f.Annotations.String(AnnotationJSONName); ok {
+		return name
+	}
+	return ApplyCase(ns.WireCase(), f.Name)
+}
+
+// GoName returns the Go name of a field of the namespace: its @go.name
+// annotation or the field name in PascalCase, since Go fields must be
+// exported to be serialized.
+func (ns *Namespace) GoName(f *Field) string {
+	if name, ok := f.Annotations.String(AnnotationGoName); ok {
+		return name
+	}
+	return casing.Pascal(f.Name)
+}
+
+// TSName returns the TypeScript name of a field of the namespace: its
+// @ts.name annotation or the field name in the ts.case of the namespace.
+func (ns *Namespace) TSName(f *Field) string {
+	if name, ok := f.Annotations.String(AnnotationTSName); ok {
+		return name
+	}
+	return ApplyCase(ns.TSCase(), f.Name)
+}
+
+// PythonName returns the Python name of a field of the namespace: its
+// @python.name annotation or the field name in the python.case of the
+// namespace.
+func (ns *Namespace) PythonName(f *Field) string {
+	if name, ok := f.Annotations.String(AnnotationPythonName); ok {
+		return name
+	}
+	return ApplyCase(ns.PythonCase(), f.Name)
+}
+
+// ApplyCase converts a field name to one of the Case values, returning it
+// unchanged for CasePreserve.
+func ApplyCase(c, name string) string {
+	switch c {
+	case CaseCamel:
+		return casing.Camel(name)
+	case CaseSnake:
+		return casing.Snake(name)
+	case CaseKebab:
+		return casing.Kebab(name)
+	case CasePascal:
+		return casing.Pascal(name)
+	default:
+		return name
+	}
+}
+
+// FlagsEncoding returns the JSON encoding of a flags enum of the namespace:
+// its @json.flags annotation or the json.flags option, FlagsInt by default.
+func (ns *Namespace) FlagsEncoding(e *Enum) string {
+	if v, ok := e.Annotations.String(AnnotationJSONFlags); ok {
+		return v
+	}
+	if v, ok := ns.inheritedOption(OptionJSONFlags); ok {
+		return v
+	}
+	return FlagsInt
+}
+
+// FieldNamespaces maps every field of the schema, including the fields of
+// inline types and of generic instances, to the namespace declaring it, which
+// is the namespace whose options apply to it (see WireName). Fields of
+// generic instances belong to the namespace of the generic type.
+func (s *Schema) FieldNamespaces() map[*Field]*Namespace {
+	owners := map[*Field]*Namespace{}
+	var declare func(ns *Namespace, fields []*Field)
+	declare = func(ns *Namespace, fields []*Field) {
+		for _, f := range fields {
+			owners[f] = ns
+			if f.Type.Inline() {
+				declare(ns, f.Type.Fields)
+			}
+		}
+	}
+
+	namespaces := s.AllNamespaces()
+	typeOwners := map[*Type]*Namespace{}
+	for _, ns := range namespaces {
+		for _, t := range ns.Types {
+			typeOwners[t] = ns
+			declare(ns, t.Fields)
+		}
+		for _, c := range ns.Consts {
+			declare(ns, c.Type.Fields)
+		}
+	}
+	for _, ns := range namespaces {
+		for _, inst := range ns.Instances {
+			if owner, ok := typeOwners[inst.Generic]; ok {
+				declare(owner, inst.Type.Fields)
+			}
+		}
+	}
+	return owners
+}
+
+// EnumNamespaces maps every enum of the schema to the namespace declaring it.
+func (s *Schema) EnumNamespaces() map[*Enum]*Namespace {
+	owners := map[*Enum]*Namespace{}
+	for _, ns := range s.AllNamespaces() {
+		for _, e := range ns.Enums {
+			owners[e] = ns
+		}
+	}
+	return owners
+}
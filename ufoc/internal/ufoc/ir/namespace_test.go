@@ -0,0 +1,140 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceTargetNames(t *testing.T) {
+	ns := &Namespace{Name: "BillingV2", DisplayName: "billing-v2"}
+	assert.Equal(t, "billingv2", ns.GoPackage())
+	assert.Equal(t, "billing-v2", ns.TSModule())
+	assert.Equal(t, "billing_v2", ns.PythonModule())
+
+	ns.Options = []*Option{
+		{Key: OptionGoPackage, Value: "billing"},
+		{Key: OptionTSModule, Value: "billing/v2"},
+		{Key: OptionPythonModule, Value: "acme.billing.v2"},
+	}
+	assert.Equal(t, "billing", ns.GoPackage())
+	assert.Equal(t, "billing/v2", ns.TSModule())
+	assert.Equal(t, "acme.billing.v2", ns.PythonModule())
+}
+
+func TestNamespaceOption(t *testing.T) {
+	ns := &Namespace{Options: []*Option{
+		{Key: OptionGoPackage, Value: "first"},
+		{Key: OptionGoPackage, Value: "second"},
+	}}
+
+	v, ok := ns.Option(OptionGoPackage)
+	assert.True(t, ok)
+	assert.Equal(t, "first", v)
+
+	_, ok = ns.Option(OptionTSModule)
+	assert.False(t, ok)
+}
+
+func TestNamespaceGoUUID(t *testing.T) {
+	ns := &Namespace{Name: "Tasks"}
+	assert.Equal(t, GoUUIDString, ns.GoUUID())
+
+	ns.Options = []*Option{{Key: OptionGoUUID, Value: GoUUIDBytes}}
+	assert.Equal(t, GoUUIDBytes, ns.GoUUID())
+}
+
+func TestNamespaceGoSpread(t *testing.T) {
+	ns := &Namespace{Name: "Tasks"}
+	assert.Equal(t, GoSpreadEmbed, ns.GoSpread())
+
+	ns.Options = []*Option{{Key: OptionGoSpread, Value: GoSpreadCopy}}
+	assert.Equal(t, GoSpreadCopy, ns.GoSpread())
+}
+
+func TestNamespaceWildcard(t *testing.T) {
+	ns := &Namespace{Name: "Tasks"}
+	assert.Equal(t, WildcardNATS, ns.Wildcard())
+
+	ns.Options = []*Option{{Key: OptionWildcard, Value: WildcardRegex}}
+	assert.Equal(t, WildcardRegex, ns.Wildcard())
+}
+
+func TestNamespaceNested(t *testing.T) {
+	billing := &Namespace{Name: "BillingV2", Options: []*Option{
+		{Key: OptionPythonModule, Value: "acme.billing"},
+		{Key: OptionGoUUID, Value: GoUUIDBytes},
+	}}
+	invoices := &Namespace{Name: "Invoices", Parent: billing}
+	lines := &Namespace{Name: "LineItems", Parent: invoices, Options: []*Option{
+		{Key: OptionGoPackage, Value: "lines"},
+		{Key: OptionGoUUID, Value: GoUUIDString},
+	}}
+	billing.Namespaces = []*Namespace{invoices}
+	invoices.Namespaces = []*Namespace{lines}
+
+	assert.Equal(t, "BillingV2.Invoices.LineItems", lines.Path())
+	assert.Same(t, billing, lines.Root())
+	assert.Equal(t, "lines", lines.GoPackage())
+	assert.Equal(t, "billingv2/invoices/lines", lines.GoPackagePath())
+	assert.Equal(t, "billing-v2/invoices/line-items", lines.TSModule())
+	assert.Equal(t, "acme.billing.invoices.line_items", lines.PythonModule())
+
+	// Choice options are inherited from the enclosing namespaces.
+	assert.Equal(t, GoUUIDBytes, invoices.GoUUID())
+	assert.Equal(t, GoUUIDString, lines.GoUUID())
+
+	schema := &Schema{Namespaces: []*Namespace{billing, {Name: "Tasks"}}}
+	var paths []string
+	for _, ns := range schema.AllNamespaces() {
+		paths = append(paths, ns.Path())
+	}
+	assert.Equal(t, []string{"BillingV2", "BillingV2.Invoices", "BillingV2.Invoices.LineItems", "Tasks"}, paths)
+}
+
+func TestNamespaceFieldNames(t *testing.T) {
+	ns := &Namespace{Name: "Tasks"}
+	field := &Field{Name: "createdAt"}
+	assert.Equal(t, "createdAt", ns.WireName(field))
+	assert.Equal(t, "CreatedAt", ns.GoName(field))
+	assert.Equal(t, "createdAt", ns.TSName(field))
+	assert.Equal(t, "createdAt", ns.PythonName(field))
+
+	ns.Options = []*Option{
+		{Key: OptionWireCase, Value: CaseSnake},
+		{Key: OptionPythonCase, Value: CaseSnake},
+	}
+	assert.Equal(t, "created_at", ns.WireName(field))
+	assert.Equal(t, "createdAt", ns.TSName(field))
+	assert.Equal(t, "created_at", ns.PythonName(field))
+
+	ns.Options = []*Option{{Key: OptionWireCase, Value: CaseKebab}}
+	assert.Equal(t, "created-at", ns.WireName(field))
+
+	// Annotations override the derived names.
+	str := func(s string) []*Value { return []*Value{{String: &s}} }
+	field.Annotations = Annotations{
+		{Name: AnnotationJSONName, Args: str("created")},
+		{Name: AnnotationGoName, Args: str("Created")},
+		{Name: AnnotationTSName, Args: str("created_at")},
+	}
+	assert.Equal(t, "created", ns.WireName(field))
+	assert.Equal(t, "Created", ns.GoName(field))
+	assert.Equal(t, "created_at", ns.TSName(field))
+}
+
+func TestNamespaceFileOptions(t *testing.T) {
+	schema := &Schema{Options: []*Option{
+		{Key: OptionWireCase, Value: CaseSnake},
+		{Key: OptionGoUUID, Value: GoUUIDBytes},
+	}}
+	billing := &Namespace{Name: "Billing", Schema: schema}
+	invoices := &Namespace{Name: "Invoices", Parent: billing, Schema: schema, Options: []*Option{
+		{Key: OptionWireCase, Value: CaseCamel},
+	}}
+
+	assert.Equal(t, CaseSnake, billing.WireCase())
+	assert.Equal(t, CaseCamel, invoices.WireCase())
+	assert.Equal(t, GoUUIDBytes, invoices.GoUUID())
+	assert.Equal(t, CasePreserve, invoices.TSCase())
+}
@@ -0,0 +1,278 @@
+package ir
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Pattern dialects, the systems a pattern is used with. The dialect sets the
+// rules of the literal text, how placeholder values are escaped and the
+// wildcard form of the pattern.
+const (
+	DialectNATS  = "nats"
+	DialectMQTT  = "mqtt"
+	DialectHTTP  = "http"
+	DialectRedis = "redis"
+)
+
+// Placeholders returns the placeholders of the pattern that are not reserved,
+// which are the parameters of the pattern.
+func (p *Pattern) Placeholders() []*Placeholder {
+	var out []*Placeholder
+	for _, s := range p.Segments {
+		if s.Placeholder != nil && !s.Placeholder.Reserved() {
+			out = append(out, s.Placeholder)
+		}
+	}
+	return out
+}
+
+// wildcardChars are the wildcards of the messaging systems patterns are used
+// with (NATS * and >, MQTT + and #).
+const wildcardChars = "*>+#"
+
+// IllegalChars returns the characters that placeholder values cannot contain:
+// whitespace, wildcards and the separators used in the literal segments, so
+// that a pattern value can always be split back into its placeholders.
+func (p *Pattern) IllegalChars() string {
+	chars := " \t\r\n" + wildcardChars
+	for _, s := range p.Segments {
+		for _, r := range s.Literal {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(chars, r) {
+				chars += string(r)
+			}
+		}
+	}
+	return chars
+}
+
+// Reserved reports whether the placeholder is {ns} or {namespace}, which are
+// replaced by the namespace name.
+func (p *Placeholder) Reserved() bool {
+	return p.Name == "ns" || p.Name == "namespace"
+}
+
+// Build returns the pattern with its placeholders replaced by values, keyed by
+// placeholder name, and the reserved placeholders replaced by ns, the display
+// name of the namespace. It fails when a value is missing or invalid. Values
+// of HTTP routes are percent-encoded instead of rejected when they contain
+// illegal characters.
+func (p *Pattern) Build(ns string, values map[string]string) (string, error) {
+	illegal := p.IllegalChars()
+	var b strings.Builder
+	for _, s := range p.Segments {
+		ph := s.Placeholder
+		switch {
+		case ph == nil:
+			b.WriteString(s.Literal)
+		case ph.Reserved():
+			b.WriteString(ns)
+		default:
+			v, ok := values[ph.Name]
+			if !ok {
+				return "", fmt.Errorf("missing value for placeholder %q", ph.Name)
+			}
+			if i := strings.IndexAny(v, illegal); i >= 0 && p.Dialect != DialectHTTP {
+				return "", fmt.Errorf("value %q of placeholder %q contains %q", v, ph.Name, v[i:i+1])
+			}
+			if err := ph.Check(v); err != nil {
+				return "", err
+			}
+			if p.Dialect == DialectHTTP {
+				v = escapePath(v, illegal)
+			}
+			b.WriteString(v)
+		}
+	}
+	return b.String(), nil
+}
+
+// Match is the reverse of Build: it reports whether s is an instance of the
+// pattern and returns the values of its placeholders.
+func (p *Pattern) Match(ns, s string) (map[string]string, bool) {
+	m := regexp.MustCompile(p.Regexp(ns)).FindStringSubmatch(s)
+	if m == nil {
+		return nil, false
+	}
+	values := map[string]string{}
+	for i, ph := range p.Placeholders() {
+		v := m[i+1]
+		if p.Dialect == DialectHTTP {
+			var err error
+			if v, err = url.PathUnescape(v); err != nil {
+				return nil, false
+			}
+		}
+		if ph.Check(v) != nil {
+			return nil, false
+		}
+		values[ph.Name] = v
+	}
+	return values, true
+}
+
+// escapePath percent-encodes the bytes of a value that are not unreserved URI
+// characters (RFC 3986) or are illegal in the pattern, so that any value can
+// be used in a path segment.
+func escapePath(v, illegal string) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if unreserved(c) && strings.IndexByte(illegal, c) < 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func unreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("-._~", c) >= 0
+}
+
+// Regexp returns a regular expression that matches the instances of the
+// pattern, with one capturing group per placeholder in order. It only uses
+// syntax shared by RE2 and Java, so it works in Go and for Kafka topic
+// subscriptions alike.
+func (p *Pattern) Regexp(ns string) string {
+	value := "([^" + classEscape(p.IllegalChars()) + "]+)"
+	var b strings.Builder
+	b.WriteString("^")
+	for _, s := range p.Segments {
+		switch {
+		case s.Placeholder == nil:
+			b.WriteString(regexp.QuoteMeta(s.Literal))
+		case s.Placeholder.Reserved():
+			b.WriteString(regexp.QuoteMeta(ns))
+		default:
+			b.WriteString(value)
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+func classEscape(chars string) string {
+	var b strings.Builder
+	for _, r := range chars {
+		switch r {
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\\', ']', '[', '^', '-':
+			b.WriteString(`\` + string(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// WildcardSyntax returns the wildcard syntax of the pattern in namespace ns:
+// the one of its dialect, WildcardRegex for HTTP routes, which have no
+// wildcards, or the pattern.wildcard option for patterns without a dialect.
+func (p *Pattern) WildcardSyntax(ns *Namespace) string {
+	switch p.Dialect {
+	case DialectNATS:
+		return WildcardNATS
+	case DialectMQTT:
+		return WildcardMQTT
+	case DialectRedis:
+		return WildcardRedis
+	case DialectHTTP:
+		return WildcardRegex
+	}
+	return ns.Wildcard()
+}
+
+// Wildcard returns the subscription form of the pattern that matches all of
+// its instances in the given wildcard syntax. NATS and MQTT wildcards match
+// whole tokens, so a token that contains a placeholder is replaced as a whole
+// and the subscriber must still Match the subjects it receives.
+func (p *Pattern) Wildcard(ns, syntax string) string {
+	var sep, wildcard string
+	switch syntax {
+	case WildcardMQTT:
+		sep, wildcard = "/", "+"
+	case WildcardRegex:
+		return p.Regexp(ns)
+	case WildcardRedis:
+		return p.glob(ns)
+	default:
+		sep, wildcard = ".", "*"
+	}
+
+	// Placeholders are marked with a NUL byte, which has no place in a
+	// subject, to find the tokens that contain them.
+	var b strings.Builder
+	for _, s := range p.Segments {
+		switch {
+		case s.Placeholder == nil:
+			b.WriteString(s.Literal)
+		case s.Placeholder.Reserved():
+			b.WriteString(ns)
+		default:
+			b.WriteByte(0)
+		}
+	}
+	tokens := strings.Split(b.String(), sep)
+	for i, t := range tokens {
+		if strings.IndexByte(t, 0) >= 0 {
+			tokens[i] = wildcard
+		}
+	}
+	return strings.Join(tokens, sep)
+}
+
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// glob returns the pattern as a Redis glob, as used by SCAN MATCH and
+// PSUBSCRIBE, with a * per placeholder and the literal text escaped.
+func (p *Pattern) glob(ns string) string {
+	var b strings.Builder
+	for _, s := range p.Segments {
+		switch {
+		case s.Placeholder == nil:
+			b.WriteString(globEscaper.Replace(s.Literal))
+		case s.Placeholder.Reserved():
+			b.WriteString(globEscaper.Replace(ns))
+		default:
+			b.WriteString("*")
+		}
+	}
+	return b.String()
+}
+
+// Check checks that value is a valid value of the placeholder type. The type
+// must have been resolved by the analyzer.
+func (p *Placeholder) Check(value string) error {
+	if p.Type == nil {
+		return nil
+	}
+	switch {
+	case p.Type.Enum != nil:
+		if !slices.ContainsFunc(p.Type.Enum.Members, func(m *EnumMember) bool { return m.WireValue() == value }) {
+			return fmt.Errorf("%q is not a value of enum %q", value, p.Type.Enum.Name)
+		}
+	case p.Type.Primitive == Bool:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%q is not a valid bool", value)
+		}
+	case p.Type.Primitive.Integer():
+		if strings.ContainsRune(value, '.') {
+			return fmt.Errorf("%q is not a valid %s", value, p.Type.Primitive)
+		}
+		return p.Type.Primitive.CheckNumber(value)
+	case p.Type.Primitive.Textual():
+		return p.Type.Primitive.CheckString(value)
+	}
+	return nil
+}
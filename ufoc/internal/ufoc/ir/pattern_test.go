@@ -0,0 +1,186 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternPlaceholders(t *testing.T) {
+	p := &Pattern{
+		Name: "TaskTopic",
+		Segments: []*PatternSegment{
+			{Placeholder: &Placeholder{Name: "ns"}},
+			{Literal: "."},
+			{Placeholder: &Placeholder{Name: "taskId"}},
+			{Literal: "/v2-"},
+			{Placeholder: &Placeholder{Name: "namespace"}},
+		},
+	}
+
+	placeholders := p.Placeholders()
+	assert.Len(t, placeholders, 1)
+	assert.Equal(t, "taskId", placeholders[0].Name)
+	assert.Equal(t, " \t\r\n*>+#./-", p.IllegalChars())
+}
+
+func TestPatternRoundTrip(t *testing.T) {
+	inProgress, archived := "in-progress", "archived.v1"
+	status := &Enum{Name: "TaskStatus", Members: []*EnumMember{
+		{Name: "PENDING"},
+		{Name: "IN_PROGRESS", Value: &Value{String: &inProgress}},
+		{Name: "ARCHIVED", Value: &Value{String: &archived}},
+	}}
+	p := &Pattern{
+		Name: "TaskTopic",
+		Segments: []*PatternSegment{
+			{Literal: "tasks."},
+			{Placeholder: &Placeholder{Name: "ns"}},
+			{Literal: "."},
+			{Placeholder: &Placeholder{Name: "taskId", Type: &TypeRef{Name: "uuid", Primitive: UUID}}},
+			{Literal: ".shard:"},
+			{Placeholder: &Placeholder{Name: "shard", Type: &TypeRef{Name: "int", Primitive: Int}}},
+			{Literal: "."},
+			{Placeholder: &Placeholder{Name: "status", Type: &TypeRef{Name: "TaskStatus", Enum: status}}},
+		},
+	}
+
+	values := map[string]string{
+		"taskId": "0b1c3a9e-8f5d-4d7a-9a43-2c9e8f1b7d20",
+		"shard":  "12",
+		"status": "PENDING",
+	}
+	s, err := p.Build("billing-v2", values)
+	require.NoError(t, err)
+	assert.Equal(t, "tasks.billing-v2.0b1c3a9e-8f5d-4d7a-9a43-2c9e8f1b7d20.shard:12.PENDING", s)
+
+	got, ok := p.Match("billing-v2", s)
+	require.True(t, ok)
+	assert.Equal(t, values, got)
+
+	values["status"] = "in-progress"
+	s, err = p.Build("billing-v2", values)
+	require.NoError(t, err)
+	got, ok = p.Match("billing-v2", s)
+	require.True(t, ok)
+	assert.Equal(t, values, got)
+
+	// The separators of the pattern cannot appear in the values.
+	values["status"] = "archived.v1"
+	_, err = p.Build("billing-v2", values)
+	assert.EqualError(t, err, `value "archived.v1" of placeholder "status" contains "."`)
+
+	_, ok = p.Match("billing-v2", "tasks.billing-v2.not-a-uuid.shard:12.PENDING")
+	assert.False(t, ok)
+	_, ok = p.Match("billing-v2", "tasks.billing-v2.0b1c3a9e-8f5d-4d7a-9a43-2c9e8f1b7d20.shard:x.PENDING")
+	assert.False(t, ok)
+	_, ok = p.Match("other", s)
+	assert.False(t, ok)
+}
+
+func TestPatternBuildErrors(t *testing.T) {
+	p := &Pattern{
+		Name: "TaskTopic",
+		Segments: []*PatternSegment{
+			{Literal: "tasks."},
+			{Placeholder: &Placeholder{Name: "taskId"}},
+			{Literal: "."},
+			{Placeholder: &Placeholder{Name: "done", Type: &TypeRef{Name: "bool", Primitive: Bool}}},
+		},
+	}
+
+	_, err := p.Build("Tasks", map[string]string{"taskId": "a"})
+	assert.EqualError(t, err, `missing value for placeholder "done"`)
+	_, err = p.Build("Tasks", map[string]string{"taskId": "a.b", "done": "true"})
+	assert.EqualError(t, err, `value "a.b" of placeholder "taskId" contains "."`)
+	_, err = p.Build("Tasks", map[string]string{"taskId": "a*", "done": "true"})
+	assert.EqualError(t, err, `value "a*" of placeholder "taskId" contains "*"`)
+	_, err = p.Build("Tasks", map[string]string{"taskId": "a", "done": "yes"})
+	assert.EqualError(t, err, `"yes" is not a valid bool`)
+}
+
+func TestPatternWildcard(t *testing.T) {
+	p := &Pattern{
+		Name: "TaskTopic",
+		Segments: []*PatternSegment{
+			{Placeholder: &Placeholder{Name: "ns"}},
+			{Literal: "/tasks/"},
+			{Placeholder: &Placeholder{Name: "taskId"}},
+			{Literal: "/updates.v"},
+			{Placeholder: &Placeholder{Name: "version"}},
+		},
+	}
+
+	// Wildcards replace whole tokens, so the MQTT wildcard also matches other
+	// versions than "v..." and NATS, which splits on ".", matches too much.
+	assert.Equal(t, "Tasks/tasks/+/+", p.Wildcard("Tasks", WildcardMQTT))
+	assert.Equal(t, "*.*", p.Wildcard("Tasks", WildcardNATS))
+	assert.Equal(t, `^Tasks/tasks/([^ \t\r\n*>+#/.]+)/updates\.v([^ \t\r\n*>+#/.]+)$`, p.Wildcard("Tasks", WildcardRegex))
+
+	nats := &Pattern{
+		Name: "TaskTopic",
+		Segments: []*PatternSegment{
+			{Literal: "tasks."},
+			{Placeholder: &Placeholder{Name: "taskId"}},
+			{Literal: ".updates"},
+		},
+	}
+	assert.Equal(t, "tasks.*.updates", nats.Wildcard("Tasks", WildcardNATS))
+	assert.Equal(t, `^tasks\.([^ \t\r\n*>+#.]+)\.updates$`, nats.Wildcard("Tasks", WildcardRegex))
+}
+
+func TestPatternHTTPEscaping(t *testing.T) {
+	p := &Pattern{
+		Name:    "FileRoute",
+		Dialect: DialectHTTP,
+		Segments: []*PatternSegment{
+			{Literal: "/files/"},
+			{Placeholder: &Placeholder{Name: "name"}},
+			{Literal: "."},
+			{Placeholder: &Placeholder{Name: "ext"}},
+		},
+	}
+
+	values := map[string]string{"name": "q3 report/v1.2", "ext": "pdf"}
+	s, err := p.Build("Files", values)
+	require.NoError(t, err)
+	assert.Equal(t, "/files/q3%20report%2Fv1%2E2.pdf", s)
+
+	got, ok := p.Match("Files", s)
+	require.True(t, ok)
+	assert.Equal(t, values, got)
+
+	_, ok = p.Match("Files", "/files/q3%zz.pdf")
+	assert.False(t, ok)
+}
+
+func TestPatternWildcardSyntax(t *testing.T) {
+	ns := &Namespace{Name: "Tasks", Options: []*Option{{Key: OptionWildcard, Value: WildcardMQTT}}}
+
+	tests := []struct {
+		dialect string
+		syntax  string
+	}{
+		{"", WildcardMQTT},
+		{DialectNATS, WildcardNATS},
+		{DialectMQTT, WildcardMQTT},
+		{DialectRedis, WildcardRedis},
+		{DialectHTTP, WildcardRegex},
+	}
+	for _, tt := range tests {
+		p := &Pattern{Name: "TaskTopic", Dialect: tt.dialect}
+		assert.Equal(t, tt.syntax, p.WildcardSyntax(ns), tt.dialect)
+	}
+
+	redis := &Pattern{
+		Name:    "TaskKey",
+		Dialect: DialectRedis,
+		Segments: []*PatternSegment{
+			{Literal: "tasks:[v2]:"},
+			{Placeholder: &Placeholder{Name: "taskId"}},
+			{Literal: ":lock"},
+		},
+	}
+	assert.Equal(t, `tasks:\[v2\]:*:lock`, redis.Wildcard("Tasks", WildcardRedis))
+}
@@ -0,0 +1,160 @@
+package ir
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Primitive is a type built into the DSL.
+type Primitive string
+
+const (
+	String   Primitive = "string"
+	Int      Primitive = "int"
+	Int32    Primitive = "int32"
+	Float    Primitive = "float"
+	Float32  Primitive = "float32"
+	Bool     Primitive = "bool"
+	Datetime Primitive = "datetime"
+	Date     Primitive = "date"
+	Time     Primitive = "time"
+	Duration Primitive = "duration"
+	UUID     Primitive = "uuid"
+	Bytes    Primitive = "bytes"
+	Decimal  Primitive = "decimal"
+)
+
+// Primitives lists every primitive type in the order used by the spec.
+var Primitives = []Primitive{
+	String, Int, Int32, Float, Float32, Bool, Datetime, Date, Time, Duration, UUID, Bytes, Decimal,
+}
+
+// JSONSchema describes how a primitive is represented in JSON Schema.
+type JSONSchema struct {
+	Type            string
+	Format          string
+	ContentEncoding string
+	Pattern         string
+}
+
+var jsonSchemas = map[Primitive]JSONSchema{
+	String:   {Type: "string"},
+	Int:      {Type: "integer", Format: "int64"},
+	Int32:    {Type: "integer", Format: "int32"},
+	Float:    {Type: "number", Format: "double"},
+	Float32:  {Type: "number", Format: "float"},
+	Bool:     {Type: "boolean"},
+	Datetime: {Type: "string", Format: "date-time"},
+	Date:     {Type: "string", Format: "date"},
+	Time:     {Type: "string", Format: "time"},
+	Duration: {Type: "string", Format: "duration"},
+	UUID:     {Type: "string", Format: "uuid"},
+	Bytes:    {Type: "string", ContentEncoding: "base64"},
+	Decimal:  {Type: "string", Pattern: `^-?\d+(\.\d+)?$`},
+}
+
+// LookupPrimitive returns the primitive with the given name.
+func LookupPrimitive(name string) (Primitive, bool) {
+	p := Primitive(name)
+	_, ok := jsonSchemas[p]
+	return p, ok
+}
+
+// JSONSchema returns the JSON Schema representation of the primitive.
+func (p Primitive) JSONSchema() JSONSchema {
+	return jsonSchemas[p]
+}
+
+// Integer reports whether the primitive holds integer numbers.
+func (p Primitive) Integer() bool {
+	return p == Int || p == Int32
+}
+
+// Numeric reports whether the primitive is encoded as a JSON number.
+func (p Primitive) Numeric() bool {
+	return p.Integer() || p == Float || p == Float32
+}
+
+// Textual reports whether the primitive is encoded as a JSON string.
+func (p Primitive) Textual() bool {
+	return p != Bool && !p.Numeric()
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	timePattern     = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d:([0-5]\d|60)(\.\d+)?(Z|[+-]([01]\d|2[0-3]):[0-5]\d)?$`)
+	durationPattern = regexp.MustCompile(`^P(\d+Y)?(\d+M)?(\d+W)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+	decimalPattern  = regexp.MustCompile(jsonSchemas[Decimal].Pattern)
+)
+
+// CheckString checks that s is a valid value of a textual primitive, e.g. a
+// canonical UUID for uuid or an ISO 8601 date for date.
+func (p Primitive) CheckString(s string) error {
+	var ok bool
+	switch p {
+	case String:
+		ok = true
+	case Datetime:
+		_, err := time.Parse(time.RFC3339Nano, s)
+		ok = err == nil
+	case Date:
+		_, err := time.Parse(time.DateOnly, s)
+		ok = err == nil
+	case Time:
+		ok = timePattern.MatchString(s)
+	case Duration:
+		ok = durationPattern.MatchString(s) && s != "P" && s[len(s)-1] != 'T'
+	case UUID:
+		ok = uuidPattern.MatchString(s)
+	case Bytes:
+		_, err := base64.StdEncoding.DecodeString(s)
+		ok = err == nil
+	case Decimal:
+		ok = decimalPattern.MatchString(s)
+	default:
+		return fmt.Errorf("%s values are not strings", p)
+	}
+	if !ok {
+		return fmt.Errorf("%q is not a valid %s", s, p)
+	}
+	return nil
+}
+
+// CheckNumber checks that the number literal n is a valid value of a numeric
+// primitive, e.g. that it is an integer within the range of int32.
+func (p Primitive) CheckNumber(n string) error {
+	switch p {
+	case Int, Int32:
+		v, err := strconv.ParseInt(n, 10, 64)
+		if err != nil {
+			if _, ferr := strconv.ParseFloat(n, 64); ferr == nil && !isRangeError(err) {
+				return fmt.Errorf("%s is not an integer", n)
+			}
+			return fmt.Errorf("%s overflows %s", n, p)
+		}
+		if p == Int32 && (v < math.MinInt32 || v > math.MaxInt32) {
+			return fmt.Errorf("%s overflows %s", n, p)
+		}
+	case Float, Float32:
+		bits := 64
+		if p == Float32 {
+			bits = 32
+		}
+		if _, err := strconv.ParseFloat(n, bits); err != nil {
+			return fmt.Errorf("%s overflows %s", n, p)
+		}
+	default:
+		return fmt.Errorf("%s values are not numbers", p)
+	}
+	return nil
+}
+
+func isRangeError(err error) bool {
+	var numErr *strconv.NumError
+	return errors.As(err, &numErr) && numErr.Err == strconv.ErrRange
+}
@@ -0,0 +1,92 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupPrimitive(t *testing.T) {
+	for _, p := range Primitives {
+		got, ok := LookupPrimitive(string(p))
+		assert.True(t, ok, p)
+		assert.Equal(t, p, got)
+	}
+
+	_, ok := LookupPrimitive("Task")
+	assert.False(t, ok)
+	_, ok = LookupPrimitive("")
+	assert.False(t, ok)
+}
+
+func TestPrimitiveJSONSchema(t *testing.T) {
+	assert.Equal(t, JSONSchema{Type: "string", Format: "uuid"}, UUID.JSONSchema())
+	assert.Equal(t, JSONSchema{Type: "string", ContentEncoding: "base64"}, Bytes.JSONSchema())
+	assert.Equal(t, JSONSchema{Type: "string", Format: "duration"}, Duration.JSONSchema())
+	assert.Equal(t, JSONSchema{Type: "integer", Format: "int32"}, Int32.JSONSchema())
+	assert.Equal(t, "string", Decimal.JSONSchema().Type)
+	assert.NotEmpty(t, Decimal.JSONSchema().Pattern)
+
+	for _, p := range Primitives {
+		assert.NotEmpty(t, p.JSONSchema().Type, p)
+	}
+}
+
+func TestPrimitiveNumeric(t *testing.T) {
+	assert.True(t, Int.Integer())
+	assert.True(t, Int32.Integer())
+	assert.False(t, Float.Integer())
+	assert.True(t, Float32.Numeric())
+	assert.False(t, Decimal.Numeric())
+	assert.False(t, String.Numeric())
+}
+
+func TestPrimitiveCheckString(t *testing.T) {
+	tests := []struct {
+		primitive Primitive
+		value     string
+		valid     bool
+	}{
+		{String, "", true},
+		{Datetime, "2024-05-01T13:45:00Z", true},
+		{Datetime, "2024-05-01T13:45:00.123+02:00", true},
+		{Datetime, "2024-05-01", false},
+		{Date, "2024-05-01", true},
+		{Date, "2024-13-01", false},
+		{Time, "13:45:00", true},
+		{Time, "13:45:00.5Z", true},
+		{Time, "25:00:00", false},
+		{Duration, "PT1H30M", true},
+		{Duration, "P1DT0.5S", true},
+		{Duration, "P", false},
+		{Duration, "P1DT", false},
+		{Duration, "1h", false},
+		{UUID, "0b1c3a9e-8f5d-4d7a-9a43-2c9e8f1b7d20", true},
+		{UUID, "0b1c3a9e8f5d4d7a9a432c9e8f1b7d20", false},
+		{Bytes, "aGVsbG8=", true},
+		{Bytes, "not base64", false},
+		{Decimal, "-19.99", true},
+		{Decimal, "1e3", false},
+		{Int, "5", false},
+	}
+
+	for _, tt := range tests {
+		err := tt.primitive.CheckString(tt.value)
+		if tt.valid {
+			assert.NoError(t, err, "%s %q", tt.primitive, tt.value)
+		} else {
+			assert.Error(t, err, "%s %q", tt.primitive, tt.value)
+		}
+	}
+}
+
+func TestPrimitiveCheckNumber(t *testing.T) {
+	assert.NoError(t, Int.CheckNumber("-42"))
+	assert.NoError(t, Int32.CheckNumber("2147483647"))
+	assert.EqualError(t, Int32.CheckNumber("2147483648"), "2147483648 overflows int32")
+	assert.EqualError(t, Int.CheckNumber("99999999999999999999"), "99999999999999999999 overflows int")
+	assert.EqualError(t, Int.CheckNumber("1.5"), "1.5 is not an integer")
+	assert.NoError(t, Float.CheckNumber("1.5"))
+	assert.NoError(t, Float32.CheckNumber(".5"))
+	assert.EqualError(t, String.CheckNumber("1"), "string values are not numbers")
+}
@@ -0,0 +1,37 @@
+package ir
+
+// WalkTypeRefs calls fn for every type reference in the namespace, including
+// the ones nested in inline object types.
+func WalkTypeRefs(ns *Namespace, fn func(*TypeRef)) {
+	for _, t := range ns.Types {
+		for _, s := range t.Spreads {
+			walkTypeRef(s.Type, fn)
+		}
+		walkFieldTypeRefs(t.Fields, fn)
+	}
+	for _, c := range ns.Consts {
+		walkTypeRef(c.Type, fn)
+	}
+	for _, p := range ns.Patterns {
+		for _, ph := range p.Placeholders() {
+			walkTypeRef(ph.Type, fn)
+		}
+	}
+}
+
+func walkFieldTypeRefs(fields []*Field, fn func(*TypeRef)) {
+	for _, f := range fields {
+		walkTypeRef(f.Type, fn)
+	}
+}
+
+func walkTypeRef(ref *TypeRef, fn func(*TypeRef)) {
+	if ref == nil {
+		return
+	}
+	fn(ref)
+	walkFieldTypeRefs(ref.Fields, fn)
+	for _, arg := range ref.Args {
+		walkTypeRef(arg, fn)
+	}
+}
@@ -0,0 +1,44 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkTypeRefs(t *testing.T) {
+	ns := &Namespace{
+		Types: []*Type{
+			{
+				Name:    "Task",
+				Spreads: []*Spread{{Type: &TypeRef{Name: "BaseEntity"}}},
+				Fields: []*Field{
+					{Name: "id", Type: &TypeRef{Name: "string"}},
+					{Name: "meta", Type: &TypeRef{Fields: []*Field{
+						{Name: "status", Type: &TypeRef{Name: "TaskStatus"}},
+					}}},
+				},
+			},
+		},
+		Consts: []*Const{
+			{Name: "MaxRetries", Type: &TypeRef{Name: "int"}},
+			{Name: "FirstPage", Type: &TypeRef{Name: "Page", Args: []*TypeRef{{Name: "Task"}}}},
+		},
+		Patterns: []*Pattern{
+			{Name: "TaskTopic", Segments: []*PatternSegment{
+				{Placeholder: &Placeholder{Name: "ns"}},
+				{Literal: "."},
+				{Placeholder: &Placeholder{Name: "taskId"}},
+				{Literal: "."},
+				{Placeholder: &Placeholder{Name: "status", Type: &TypeRef{Name: "TaskStatus"}}},
+			}},
+		},
+	}
+
+	var names []string
+	WalkTypeRefs(ns, func(ref *TypeRef) {
+		names = append(names, ref.Name)
+	})
+
+	assert.Equal(t, []string{"BaseEntity", "string", "", "TaskStatus", "int", "Page", "Task", "TaskStatus"}, names)
+}
@@ -8,11 +8,11 @@ var Def = lexer.MustSimple([]lexer.SimpleRule{
 	{Name: "Comment", Pattern: `//[^\n]*`},
 	{Name: "BlockComment", Pattern: `/\*[^*]*\*+(?:[^/*][^*]*\*+)*/`},
 	{Name: "Docstring", Pattern: `"""[^"]*(?:"[^"][^"]*|""[^"][^"]*)*"""`},
-	{Name: "Keyword", Pattern: `\b(?:version|namespace|type|enum|const|pattern|deprecated)\b`},
-	{Name: "Number", Pattern: `[-+]?(?:\d*\.)?\d+`},
+	{Name: "Keyword", Pattern: `\b(?:version|namespace|option|type|enum|const|pattern|deprecated|internal)\b`},
+	{Name: "Number", Pattern: `(?:\d*\.)?\d+`},
 	{Name: "String", Pattern: `"(?:[^"\\]|\\["\\/bfnrt]|\\u[0-9a-fA-F]{4})*"`},
-	{Name: "Ident", Pattern: `[a-zA-Z][a-zA-Z0-9]*`},
-	{Name: "Punct", Pattern: `[{}()\[\]:=,?]`},
+	{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_]*`},
+	{Name: "Punct", Pattern: `\.\.\.|[{}()\[\]<>:=,?.+\-*/%@|]`},
 	{Name: "BlankLine", Pattern: `\n[ \t]*\n`},
 	{Name: "Newline", Pattern: `\n`},
 	{Name: "Whitespace", Pattern: `[ \t\r]+`},
@@ -43,6 +43,14 @@ func TestLexerKeywords(t *testing.T) {
 			{Type: symbols["Keyword"], Value: "deprecated"},
 			{Type: symbols["EOF"], Value: ""},
 		}},
+		{"option", "option", []lexer.Token{
+			{Type: symbols["Keyword"], Value: "option"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"internal", "internal", []lexer.Token{
+			{Type: symbols["Keyword"], Value: "internal"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
 	}
 
 	for _, tt := range tests {
@@ -76,6 +84,22 @@ func TestLexerIdentifiers(t *testing.T) {
 			{Type: symbols["Ident"], Value: "var123"},
 			{Type: symbols["EOF"], Value: ""},
 		}},
+		{"screaming_snake", "CREDIT_CARD", []lexer.Token{
+			{Type: symbols["Ident"], Value: "CREDIT_CARD"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"snake_case", "task_status", []lexer.Token{
+			{Type: symbols["Ident"], Value: "task_status"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"leading_underscore", "_internal", []lexer.Token{
+			{Type: symbols["Ident"], Value: "_internal"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"keyword_prefix", "type_name", []lexer.Token{
+			{Type: symbols["Ident"], Value: "type_name"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
 	}
 
 	for _, tt := range tests {
@@ -98,11 +122,13 @@ func TestLexerNumbers(t *testing.T) {
 			{Type: symbols["EOF"], Value: ""},
 		}},
 		{"negative", "-10", []lexer.Token{
-			{Type: symbols["Number"], Value: "-10"},
+			{Type: symbols["Punct"], Value: "-"},
+			{Type: symbols["Number"], Value: "10"},
 			{Type: symbols["EOF"], Value: ""},
 		}},
 		{"positive", "+5", []lexer.Token{
-			{Type: symbols["Number"], Value: "+5"},
+			{Type: symbols["Punct"], Value: "+"},
+			{Type: symbols["Number"], Value: "5"},
 			{Type: symbols["EOF"], Value: ""},
 		}},
 		{"float", "3.14", []lexer.Token{
@@ -114,7 +140,14 @@ func TestLexerNumbers(t *testing.T) {
 			{Type: symbols["EOF"], Value: ""},
 		}},
 		{"negative_float", "-2.718", []lexer.Token{
-			{Type: symbols["Number"], Value: "-2.718"},
+			{Type: symbols["Punct"], Value: "-"},
+			{Type: symbols["Number"], Value: "2.718"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"subtraction", "10-2", []lexer.Token{
+			{Type: symbols["Number"], Value: "10"},
+			{Type: symbols["Punct"], Value: "-"},
+			{Type: symbols["Number"], Value: "2"},
 			{Type: symbols["EOF"], Value: ""},
 		}},
 	}
@@ -285,6 +318,46 @@ func TestLexerPunctuation(t *testing.T) {
 			{Type: symbols["Punct"], Value: "?"},
 			{Type: symbols["EOF"], Value: ""},
 		}},
+		{"dot", ".", []lexer.Token{
+			{Type: symbols["Punct"], Value: "."},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"less_than", "<", []lexer.Token{
+			{Type: symbols["Punct"], Value: "<"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"greater_than", ">", []lexer.Token{
+			{Type: symbols["Punct"], Value: ">"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"ellipsis", "...", []lexer.Token{
+			{Type: symbols["Punct"], Value: "..."},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"operators", "+-*/%", []lexer.Token{
+			{Type: symbols["Punct"], Value: "+"},
+			{Type: symbols["Punct"], Value: "-"},
+			{Type: symbols["Punct"], Value: "*"},
+			{Type: symbols["Punct"], Value: "/"},
+			{Type: symbols["Punct"], Value: "%"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"annotation", `@go.name("ID")`, []lexer.Token{
+			{Type: symbols["Punct"], Value: "@"},
+			{Type: symbols["Ident"], Value: "go"},
+			{Type: symbols["Punct"], Value: "."},
+			{Type: symbols["Ident"], Value: "name"},
+			{Type: symbols["Punct"], Value: "("},
+			{Type: symbols["String"], Value: `"ID"`},
+			{Type: symbols["Punct"], Value: ")"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
+		{"nullable", `string|null`, []lexer.Token{
+			{Type: symbols["Ident"], Value: "string"},
+			{Type: symbols["Punct"], Value: "|"},
+			{Type: symbols["Ident"], Value: "null"},
+			{Type: symbols["EOF"], Value: ""},
+		}},
 	}
 
 	for _, tt := range tests {
@@ -403,8 +476,40 @@ func TestLexerComplexExamples(t *testing.T) {
 				{Type: symbols["EOF"], Value: ""},
 			},
 		},
+		{
+			"nested_type_arguments",
+			"Page<List<T>>",
+			[]lexer.Token{
+				{Type: symbols["Ident"], Value: "Page"},
+				{Type: symbols["Punct"], Value: "<"},
+				{Type: symbols["Ident"], Value: "List"},
+				{Type: symbols["Punct"], Value: "<"},
+				{Type: symbols["Ident"], Value: "T"},
+				{Type: symbols["Punct"], Value: ">"},
+				{Type: symbols["Punct"], Value: ">"},
+				{Type: symbols["EOF"], Value: ""},
+			},
+		},
+		{
+			"spread",
+			"...BaseEntity",
+			[]lexer.Token{
+				{Type: symbols["Punct"], Value: "..."},
+				{Type: symbols["Ident"], Value: "BaseEntity"},
+				{Type: symbols["EOF"], Value: ""},
+			},
+		},
+		{
+			"dotted_option_key",
+			"go.package",
+			[]lexer.Token{
+				{Type: symbols["Ident"], Value: "go"},
+				{Type: symbols["Punct"], Value: "."},
+				{Type: symbols["Ident"], Value: "package"},
+				{Type: symbols["EOF"], Value: ""},
+			},
+		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			assertTokens(t, tt.input, tt.expected)
@@ -0,0 +1,517 @@
+// Package loader reads a .ufoc file from disk, parses it and lowers the AST
+// into the resolved ir model, resolving everything that depends on the file
+// system (like external documentation files) along the way.
+package loader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/casing"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+	"github.com/uforg/ufocontract/internal/ufoc/docstring"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+	ufoclexer "github.com/uforg/ufocontract/internal/ufoc/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/parser"
+)
+
+// Result is the outcome of loading a contract file.
+type Result struct {
+	Schema      *ir.Schema
+	Diagnostics []diagnostic.Diagnostic
+	// Dependencies lists the absolute paths of every file the schema depends
+	// on: the contract file first, followed by the referenced Markdown files in
+	// order of appearance. Missing files are included as well, so watchers can
+	// pick them up once they are created.
+	Dependencies []string
+}
+
+// Load reads, parses and lowers the contract file at path. The returned error
+// is only set when the file cannot be read or parsed; problems found while
+// lowering are reported as diagnostics.
+func Load(path string) (*Result, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read contract file: %w", err)
+	}
+
+	return LoadBytes(path, src)
+}
+
+// LoadBytes is like Load but uses src as the content of the contract file
+// instead of reading it. External documentation files are still resolved
+// relative to the directory of path.
+func LoadBytes(path string, src []byte) (*Result, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path %q: %w", path, err)
+	}
+
+	file, err := parser.Parser.ParseBytes(path, src)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &loader{
+		dir:    filepath.Dir(abs),
+		result: &Result{Dependencies: []string{abs}},
+		seen:   map[string]bool{abs: true},
+	}
+	l.result.Schema = l.lowerFile(file)
+
+	return l.result, nil
+}
+
+var nameTokens = map[lexer.TokenType]bool{
+	ufoclexer.Def.Symbols()["Ident"]:   true,
+	ufoclexer.Def.Symbols()["Keyword"]: true,
+	ufoclexer.Def.Symbols()["String"]:  true,
+}
+
+type loader struct {
+	dir    string
+	result *Result
+	seen   map[string]bool
+}
+
+func (l *loader) addDependency(path string) {
+	if l.seen[path] {
+		return
+	}
+	l.seen[path] = true
+	l.result.Dependencies = append(l.result.Dependencies, path)
+}
+
+func (l *loader) errorf(pos lexer.Position, format string, args ...any) {
+	l.result.Diagnostics = append(l.result.Diagnostics, diagnostic.Errorf(pos, format, args...))
+}
+
+func (l *loader) lowerFile(file *parser.File) *ir.Schema {
+	schema := &ir.Schema{Version: file.Version}
+	for _, child := range file.Children {
+		switch {
+		case child.Docstring != nil:
+			schema.Docs = append(schema.Docs, l.lowerDoc(child.Docstring.Pos, &child.Docstring.Text))
+		case child.Option != nil:
+			schema.Options = append(schema.Options, lowerOption(child.Option))
+		case child.Namespace != nil:
+			schema.Namespaces = append(schema.Namespaces, l.lowerNamespace(child.Namespace, schema, nil))
+		}
+	}
+	return schema
+}
+
+func (l *loader) lowerNamespace(n *parser.Namespace, schema *ir.Schema, parent *ir.Namespace) *ir.Namespace {
+	ns := &ir.Namespace{
+		Pos:         n.Pos,
+		NamePos:     namePos(n.Tokens, n.Name, n.Pos),
+		Doc:         l.lowerDoc(n.Pos, n.Docstring),
+		Name:        n.Name,
+		DisplayName: n.Name,
+		Parent:      parent,
+		Schema:      schema,
+	}
+	if strings.HasPrefix(n.Name, `"`) {
+		ns.DisplayName = unquote(n.Name)
+		ns.Name = casing.Pascal(ns.DisplayName)
+	}
+	for _, child := range n.Children {
+		switch {
+		case child.Docstring != nil:
+			ns.Docs = append(ns.Docs, l.lowerDoc(child.Docstring.Pos, &child.Docstring.Text))
+		case child.Option != nil:
+			ns.Options = append(ns.Options, lowerOption(child.Option))
+		case child.Type != nil:
+			ns.Types = append(ns.Types, l.lowerType(child.Type))
+		case child.Enum != nil:
+			ns.Enums = append(ns.Enums, l.lowerEnum(child.Enum))
+		case child.Const != nil:
+			ns.Consts = append(ns.Consts, l.lowerConst(child.Const))
+		case child.Pattern != nil:
+			ns.Patterns = append(ns.Patterns, l.lowerPattern(child.Pattern))
+		case child.Namespace != nil:
+			ns.Namespaces = append(ns.Namespaces, l.lowerNamespace(child.Namespace, schema, ns))
+		}
+	}
+	return ns
+}
+
+func lowerOption(o *parser.NamespaceOption) *ir.Option {
+	return &ir.Option{
+		Pos:   o.Pos,
+		Key:   o.Key,
+		Value: unquote(o.Value),
+	}
+}
+
+func (l *loader) lowerType(t *parser.TypeDef) *ir.Type {
+	tokens := bodyTokens(t.Tokens, t.Annotations, t.Deprecated)
+	typ := &ir.Type{
+		Pos:         t.Pos,
+		NamePos:     namePos(tokens, t.Name, t.Pos),
+		Doc:         l.lowerDoc(t.Pos, t.Docstring),
+		Annotations: lowerAnnotations(t.Annotations),
+		Deprecated:  lowerDeprecated(t.Deprecated),
+		Internal:    t.Internal,
+		Name:        t.Name,
+	}
+	for _, param := range t.TypeParams {
+		typ.TypeParams = append(typ.TypeParams, &ir.TypeParam{
+			Pos:  namePos(tokens, param, t.Pos),
+			Name: param,
+		})
+	}
+
+	var fields []*parser.Field
+	for _, f := range t.Fields {
+		if f.Spread != nil {
+			typ.Spreads = append(typ.Spreads, &ir.Spread{Pos: f.Pos, Type: l.lowerTypeRef(f.Spread)})
+			continue
+		}
+		fields = append(fields, f)
+	}
+	typ.Fields = l.lowerFields(fields)
+
+	return typ
+}
+
+func (l *loader) lowerFields(fields []*parser.Field) []*ir.Field {
+	out := make([]*ir.Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Spread != nil {
+			l.errorf(f.Pos, "spreads are only allowed in type definitions")
+			continue
+		}
+		out = append(out, &ir.Field{
+			Pos:         f.Pos,
+			NamePos:     namePos(bodyTokens(f.Tokens, f.Annotations, nil), f.Name, f.Pos),
+			Doc:         l.lowerDoc(f.Pos, f.Docstring),
+			Annotations: lowerAnnotations(f.Annotations),
+			Name:        f.Name,
+			Optional:    f.Optional,
+			Nullable:    f.Nullable,
+			Type:        l.lowerTypeRef(f.Type),
+			Default:     lowerValue(f.Default),
+		})
+	}
+	return out
+}
+
+func (l *loader) lowerTypeRef(t *parser.TypeRef) *ir.TypeRef {
+	ref := &ir.TypeRef{Pos: t.Pos, Array: t.Array}
+	switch {
+	case t.Named != nil:
+		ref.Name = *t.Named
+	case t.Inline != nil:
+		ref.Fields = l.lowerFields(t.Inline.Fields)
+	}
+	for _, arg := range t.Args {
+		ref.Args = append(ref.Args, l.lowerTypeRef(arg))
+	}
+	return ref
+}
+
+func (l *loader) lowerEnum(e *parser.EnumDef) *ir.Enum {
+	enum := &ir.Enum{
+		Pos:         e.Pos,
+		NamePos:     namePos(bodyTokens(e.Tokens, e.Annotations, e.Deprecated), e.Name, e.Pos),
+		Doc:         l.lowerDoc(e.Pos, e.Docstring),
+		Annotations: lowerAnnotations(e.Annotations),
+		Deprecated:  lowerDeprecated(e.Deprecated),
+		Internal:    e.Internal,
+		Name:        e.Name,
+	}
+	if e.BaseType != nil {
+		enum.BaseType = *e.BaseType
+	}
+	for _, m := range e.Members {
+		enum.Members = append(enum.Members, &ir.EnumMember{
+			Pos:         m.Pos,
+			NamePos:     namePos(bodyTokens(m.Tokens, m.Annotations, nil), m.Name, m.Pos),
+			Doc:         l.lowerDoc(m.Pos, m.Docstring),
+			Annotations: lowerAnnotations(m.Annotations),
+			Name:        m.Name,
+			Value:       lowerValue(m.Value),
+		})
+	}
+	return enum
+}
+
+func (l *loader) lowerConst(c *parser.ConstDef) *ir.Const {
+	return &ir.Const{
+		Pos:         c.Pos,
+		NamePos:     namePos(bodyTokens(c.Tokens, c.Annotations, c.Deprecated), c.Name, c.Pos),
+		Doc:         l.lowerDoc(c.Pos, c.Docstring),
+		Annotations: lowerAnnotations(c.Annotations),
+		Deprecated:  lowerDeprecated(c.Deprecated),
+		Internal:    c.Internal,
+		Name:        c.Name,
+		Type:        l.lowerTypeRef(c.Type),
+		Value:       lowerValue(c.Value),
+	}
+}
+
+func (l *loader) lowerPattern(p *parser.PatternDef) *ir.Pattern {
+	tokens := bodyTokens(p.Tokens, p.Annotations, p.Deprecated)
+	pattern := &ir.Pattern{
+		Pos:         p.Pos,
+		NamePos:     namePos(tokens, p.Name, p.Pos),
+		Doc:         l.lowerDoc(p.Pos, p.Docstring),
+		Annotations: lowerAnnotations(p.Annotations),
+		Deprecated:  lowerDeprecated(p.Deprecated),
+		Internal:    p.Internal,
+		Name:        p.Name,
+		Pattern:     unquote(p.Pattern.Raw),
+	}
+
+	if p.Dialect != nil {
+		pattern.Dialect = *p.Dialect
+		// The dialect comes after the name, which can be spelled the same.
+		for i, tok := range tokens {
+			if tok.Pos == pattern.NamePos {
+				pattern.DialectPos = namePos(tokens[i+1:], pattern.Dialect, p.Pos)
+				break
+			}
+		}
+	}
+
+	strPos := namePos(tokens, p.Pattern.Raw, p.Pos)
+	for _, seg := range p.Pattern.Segments {
+		segment := &ir.PatternSegment{Literal: seg.Literal}
+		if seg.Literal != "" {
+			segment.Pos = advance(strPos, p.Pattern.Raw[:seg.Offset])
+		}
+		if ph := seg.Placeholder; ph != nil {
+			placeholder := &ir.Placeholder{
+				Pos:  advance(strPos, p.Pattern.Raw[:ph.NameOffset]),
+				Name: ph.Name,
+			}
+			if ph.Type != "" {
+				placeholder.Type = &ir.TypeRef{Pos: advance(strPos, p.Pattern.Raw[:ph.TypeOffset]), Name: ph.Type}
+			}
+			segment.Placeholder = placeholder
+		}
+		pattern.Segments = append(pattern.Segments, segment)
+	}
+
+	return pattern
+}
+
+// advance returns the position reached after reading text from pos.
+func advance(pos lexer.Position, text string) lexer.Position {
+	for _, r := range text {
+		pos.Offset += utf8.RuneLen(r)
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	return pos
+}
+
+// lowerDoc lowers a docstring found at pos. Associated docstrings are always
+// the first token of their definition, so the definition position is also the
+// docstring position.
+func (l *loader) lowerDoc(pos lexer.Position, raw *string) *ir.Doc {
+	if raw == nil {
+		return nil
+	}
+
+	doc := &ir.Doc{Pos: pos, Raw: *raw, Text: docstring.Normalize(*raw)}
+
+	rel, ok := externalDocPath(*raw)
+	if !ok {
+		return doc
+	}
+
+	path := filepath.Join(l.dir, filepath.FromSlash(rel))
+	doc.Source = path
+	doc.Text = ""
+	l.addDependency(path)
+
+	content, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		l.errorf(pos, "documentation file %q not found", rel)
+	case err != nil:
+		l.errorf(pos, "failed to read documentation file %q: %v", rel, err)
+	default:
+		doc.Text = docstring.NormalizeMarkdown(string(content))
+	}
+
+	return doc
+}
+
+// externalDocPath returns the referenced file when the whole content of the
+// docstring is a relative path to a Markdown file, e.g. """ ./docs/tasks.md """.
+func externalDocPath(raw string) (string, bool) {
+	content := docstring.Normalize(raw)
+	if content == "" || strings.ContainsAny(content, " \t\r\n") {
+		return "", false
+	}
+	if !strings.HasSuffix(strings.ToLower(content), ".md") {
+		return "", false
+	}
+	if filepath.IsAbs(content) || strings.HasPrefix(content, "/") || strings.Contains(content, "://") {
+		return "", false
+	}
+	return content, true
+}
+
+// namePos returns the position of the name among the tokens of a definition,
+// falling back to the definition position. Field names may also be keywords
+// used as contextual identifiers, and namespace names may be quoted.
+func namePos(tokens []lexer.Token, name string, fallback lexer.Position) lexer.Position {
+	for _, tok := range tokens {
+		if nameTokens[tok.Type] && tok.Value == name {
+			return tok.Pos
+		}
+	}
+	return fallback
+}
+
+// bodyTokens returns the tokens of a definition that follow its annotations
+// and deprecated modifier, so that names are not looked up in their
+// arguments.
+func bodyTokens(tokens []lexer.Token, annotations []*parser.Annotation, deprecated *parser.Deprecated) []lexer.Token {
+	end := 0
+	if n := len(annotations); n > 0 {
+		end = annotations[n-1].EndPos.Offset
+	}
+	if deprecated != nil {
+		end = deprecated.EndPos.Offset
+	}
+	for i, tok := range tokens {
+		if tok.Pos.Offset >= end {
+			return tokens[i:]
+		}
+	}
+	return nil
+}
+
+func lowerDeprecated(d *parser.Deprecated) *ir.Deprecation {
+	if d == nil {
+		return nil
+	}
+	if d.Message == nil {
+		return &ir.Deprecation{}
+	}
+	return &ir.Deprecation{Message: unquote(*d.Message)}
+}
+
+func lowerAnnotations(annotations []*parser.Annotation) ir.Annotations {
+	var out ir.Annotations
+	for _, a := range annotations {
+		annotation := &ir.Annotation{Pos: a.Pos, Name: a.Name}
+		for _, arg := range a.Args {
+			annotation.Args = append(annotation.Args, lowerValue(arg))
+		}
+		out = append(out, annotation)
+	}
+	return out
+}
+
+func lowerValue(v *parser.Value) *ir.Value {
+	if v == nil {
+		return nil
+	}
+	operands, ops := flattenValue(v)
+	return buildExpr(operands, ops)
+}
+
+// flattenValue returns the operands and operators of the binary expression
+// chain starting at v, in source order.
+func flattenValue(v *parser.Value) ([]*ir.Value, []string) {
+	var operands []*ir.Value
+	var ops []string
+	if v.Negate != nil {
+		// Negation binds tighter than any binary operator, so it only applies to
+		// the first operand of the chain that follows it.
+		operands, ops = flattenValue(v.Negate)
+		operands[0] = &ir.Value{Pos: v.Pos, Unary: &ir.UnaryExpr{Op: "-", Operand: operands[0]}}
+	} else {
+		operands = []*ir.Value{lowerOperand(v)}
+	}
+	if v.Next != nil {
+		next, nextOps := flattenValue(v.Next)
+		operands = append(operands, next...)
+		ops = append(append(ops, v.Op), nextOps...)
+	}
+	return operands, ops
+}
+
+var precedence = map[string]int{"+": 1, "-": 1, "*": 2, "/": 2, "%": 2}
+
+// buildExpr builds the expression tree of a chain of operands with left
+// associative operators.
+func buildExpr(operands []*ir.Value, ops []string) *ir.Value {
+	out := operands[:1:1]
+	var pending []string
+	reduce := func() {
+		op := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		left, right := out[len(out)-2], out[len(out)-1]
+		out = append(out[:len(out)-2], &ir.Value{
+			Pos:    left.Pos,
+			Binary: &ir.BinaryExpr{Op: op, Left: left, Right: right},
+		})
+	}
+	for i, op := range ops {
+		for len(pending) > 0 && precedence[pending[len(pending)-1]] >= precedence[op] {
+			reduce()
+		}
+		pending = append(pending, op)
+		out = append(out, operands[i+1])
+	}
+	for len(pending) > 0 {
+		reduce()
+	}
+	return out[0]
+}
+
+func lowerOperand(v *parser.Value) *ir.Value {
+	if v.Group != nil {
+		return lowerValue(v.Group)
+	}
+
+	value := &ir.Value{Pos: v.Pos, Number: v.Number, Ident: v.Ident}
+	switch {
+	case v.String != nil:
+		s := unquote(*v.String)
+		value.String = &s
+	case v.Array != nil:
+		value.Array = &ir.ArrayValue{}
+		for _, item := range v.Array.Items {
+			value.Array.Items = append(value.Array.Items, lowerValue(item))
+		}
+	case v.Object != nil:
+		value.Object = &ir.ObjectValue{}
+		for _, e := range v.Object.Entries {
+			value.Object.Entries = append(value.Object.Entries, &ir.ObjectEntry{
+				Pos:   e.Pos,
+				Key:   e.Key,
+				Value: lowerValue(e.Value),
+			})
+		}
+	}
+	return value
+}
+
+// unquote decodes a String token. The lexer only accepts JSON string syntax,
+// so the JSON decoder handles every escape sequence.
+func unquote(s string) string {
+	var out string
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		return s
+	}
+	return out
+}
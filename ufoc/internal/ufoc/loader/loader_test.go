@@ -0,0 +1,496 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+)
+
+func TestLoadLowersDefinitions(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", `
+		version 1
+		namespace Tasks {
+			deprecated("Use Task instead")
+			type OldTask {
+				id: string
+				tags?: string[]
+				meta: { key: string }
+			}
+
+			enum Code: int {
+				UNKNOWN = 1
+			}
+
+			const ErrorQueue: string = "tasks.failed!"
+
+			pattern TaskTopic = "{ns}.{taskId}.updates"
+		}
+	`)
+
+	res, err := Load(path)
+	require.NoError(t, err)
+	require.Empty(t, res.Diagnostics)
+
+	require.Len(t, res.Schema.Namespaces, 1)
+	ns := res.Schema.Namespaces[0]
+	assert.Equal(t, "Tasks", ns.Name)
+
+	require.Len(t, ns.Types, 1)
+	typ := ns.Types[0]
+	assert.Equal(t, "OldTask", typ.Name)
+	require.NotNil(t, typ.Deprecated)
+	assert.Equal(t, "Use Task instead", typ.Deprecated.Message)
+	require.Len(t, typ.Fields, 3)
+	assert.Equal(t, "string", typ.Fields[0].Type.Name)
+	assert.True(t, typ.Fields[1].Optional)
+	assert.True(t, typ.Fields[1].Type.Array)
+	assert.True(t, typ.Fields[2].Type.Inline())
+	assert.Equal(t, "key", typ.Fields[2].Type.Fields[0].Name)
+
+	require.Len(t, ns.Enums, 1)
+	assert.Equal(t, "int", ns.Enums[0].BaseType)
+	assert.Equal(t, "1", *ns.Enums[0].Members[0].Value.Number)
+
+	require.Len(t, ns.Consts, 1)
+	assert.Equal(t, "tasks.failed!", *ns.Consts[0].Value.String)
+
+	require.Len(t, ns.Patterns, 1)
+	assert.Equal(t, "{ns}.{taskId}.updates", ns.Patterns[0].Pattern)
+}
+
+func TestLoadNamePositions(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\n\"\"\" Docs \"\"\"\ndeprecated\ntype Task {\n  \"\"\" Docs \"\"\" id: string\n}\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	ns := res.Schema.Namespaces[0]
+	assert.Equal(t, 2, ns.NamePos.Line)
+	assert.Equal(t, 11, ns.NamePos.Column)
+
+	typ := ns.Types[0]
+	assert.Equal(t, 3, typ.Pos.Line)
+	assert.Equal(t, 5, typ.NamePos.Line)
+	assert.Equal(t, 6, typ.NamePos.Column)
+
+	field := typ.Fields[0]
+	assert.Equal(t, 3, field.Pos.Column)
+	assert.Equal(t, 16, field.NamePos.Column)
+}
+
+func TestLoadKeywordFieldNamePosition(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "events.ufoc", "version 1\nnamespace Events {\ntype Event {\n  \"\"\" Kind \"\"\" type: string\n}\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	field := res.Schema.Namespaces[0].Types[0].Fields[0]
+	assert.Equal(t, "type", field.Name)
+	assert.Equal(t, 4, field.NamePos.Line)
+	assert.Equal(t, 16, field.NamePos.Column)
+}
+
+func TestLoadSpreads(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "users.ufoc", `
+		version 1
+		namespace Users {
+			type User {
+				...BaseEntity
+				email: string
+				...Audited
+			}
+		}
+	`)
+
+	res, err := Load(path)
+	require.NoError(t, err)
+	require.Empty(t, res.Diagnostics)
+
+	user := res.Schema.Namespaces[0].Types[0]
+	require.Len(t, user.Spreads, 2)
+	assert.Equal(t, "BaseEntity", user.Spreads[0].Type.Name)
+	assert.Equal(t, 5, user.Spreads[0].Pos.Line)
+	assert.Equal(t, "Audited", user.Spreads[1].Type.Name)
+	require.Len(t, user.Fields, 1)
+	assert.Equal(t, "email", user.Fields[0].Name)
+}
+
+func TestLoadNullableFields(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\ntype Patch {\nnote?: string | null\ndue?: date\n}\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	fields := res.Schema.Namespaces[0].Types[0].Fields
+	require.Len(t, fields, 2)
+	assert.True(t, fields[0].Optional)
+	assert.True(t, fields[0].Nullable)
+	assert.True(t, fields[1].Optional)
+	assert.False(t, fields[1].Nullable)
+}
+
+func TestLoadSpreadInInlineType(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "users.ufoc", "version 1\nnamespace Users {\ntype User {\nmeta: { ...Base }\n}\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	require.Len(t, res.Diagnostics, 1)
+	assert.Equal(t, "spreads are only allowed in type definitions", res.Diagnostics[0].Message)
+	assert.Equal(t, 4, res.Diagnostics[0].Pos.Line)
+	assert.Empty(t, res.Schema.Namespaces[0].Types[0].Fields[0].Type.Fields)
+}
+
+func TestLoadGenerics(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\ntype Page<T, U> {\nitems: T[]\n}\ntype List {\npage: Page<Task, string[]>\n}\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	page := res.Schema.Namespaces[0].Types[0]
+	require.Len(t, page.TypeParams, 2)
+	assert.Equal(t, "T", page.TypeParams[0].Name)
+	assert.Equal(t, 3, page.TypeParams[0].Pos.Line)
+	assert.Equal(t, 11, page.TypeParams[0].Pos.Column)
+	assert.Equal(t, "U", page.TypeParams[1].Name)
+	assert.Equal(t, 14, page.TypeParams[1].Pos.Column)
+
+	ref := res.Schema.Namespaces[0].Types[1].Fields[0].Type
+	assert.Equal(t, "Page", ref.Name)
+	require.Len(t, ref.Args, 2)
+	assert.Equal(t, "Task", ref.Args[0].Name)
+	assert.Equal(t, "string", ref.Args[1].Name)
+	assert.True(t, ref.Args[1].Array)
+}
+
+func TestLoadFieldDefaults(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\ntype ListOptions {\npageSize: int = 25\nquery: string = \"a\\\"b\"\nsort: string\n}\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	fields := res.Schema.Namespaces[0].Types[0].Fields
+	require.NotNil(t, fields[0].Default)
+	assert.Equal(t, "25", *fields[0].Default.Number)
+	assert.Equal(t, 4, fields[0].Default.Pos.Line)
+	assert.Equal(t, 17, fields[0].Default.Pos.Column)
+	require.NotNil(t, fields[1].Default)
+	assert.Equal(t, `a"b`, *fields[1].Default.String)
+	assert.Nil(t, fields[2].Default)
+}
+
+func TestLoadStructuredValues(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\nconst Policy: RetryPolicy = {\n  regions: [\"eu\"],\n  maxAttempts: 3\n}\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	value := res.Schema.Namespaces[0].Consts[0].Value
+	require.NotNil(t, value.Object)
+	require.Len(t, value.Object.Entries, 2)
+
+	regions := value.Object.Entry("regions")
+	require.NotNil(t, regions)
+	assert.Equal(t, 4, regions.Pos.Line)
+	assert.Equal(t, 3, regions.Pos.Column)
+	require.Len(t, regions.Value.Array.Items, 1)
+	assert.Equal(t, "eu", *regions.Value.Array.Items[0].String)
+	assert.Equal(t, "3", *value.Object.Entry("maxAttempts").Value.Number)
+}
+
+func TestLoadConstExpressions(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\nconst A: int = 1 + 2 * 3 - 4\nconst B: int = -(1 + 2) * 3\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	// (1 + (2 * 3)) - 4
+	a := res.Schema.Namespaces[0].Consts[0].Value
+	require.NotNil(t, a.Binary)
+	assert.Equal(t, "-", a.Binary.Op)
+	assert.Equal(t, "4", *a.Binary.Right.Number)
+	sum := a.Binary.Left.Binary
+	require.NotNil(t, sum)
+	assert.Equal(t, "+", sum.Op)
+	assert.Equal(t, "1", *sum.Left.Number)
+	assert.Equal(t, "*", sum.Right.Binary.Op)
+	assert.Equal(t, 3, a.Pos.Line)
+	assert.Equal(t, 16, a.Pos.Column)
+
+	// (-(1 + 2)) * 3
+	b := res.Schema.Namespaces[0].Consts[1].Value
+	require.NotNil(t, b.Binary)
+	assert.Equal(t, "*", b.Binary.Op)
+	neg := b.Binary.Left.Unary
+	require.NotNil(t, neg)
+	assert.Equal(t, "+", neg.Operand.Binary.Op)
+}
+
+func TestLoadPatternPlaceholders(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\n  pattern TaskTopic = \"{ns}.{taskId: uuid}.{ status }\"\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	p := res.Schema.Namespaces[0].Patterns[0]
+	assert.Equal(t, "{ns}.{taskId: uuid}.{ status }", p.Pattern)
+	require.Len(t, p.Segments, 5)
+	assert.True(t, p.Segments[0].Placeholder.Reserved())
+	assert.Equal(t, ".", p.Segments[1].Literal)
+	assert.Equal(t, 28, p.Segments[1].Pos.Column)
+	assert.Empty(t, p.Dialect)
+
+	taskID := p.Segments[2].Placeholder
+	assert.Equal(t, "taskId", taskID.Name)
+	assert.Equal(t, 3, taskID.Pos.Line)
+	assert.Equal(t, 30, taskID.Pos.Column)
+	require.NotNil(t, taskID.Type)
+	assert.Equal(t, "uuid", taskID.Type.Name)
+	assert.Equal(t, 38, taskID.Type.Pos.Column)
+
+	status := p.Segments[4].Placeholder
+	assert.Equal(t, "status", status.Name)
+	assert.Equal(t, 46, status.Pos.Column)
+	assert.Nil(t, status.Type)
+}
+
+func TestLoadPatternDialect(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\n  pattern http: http = \"/tasks\"\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	p := res.Schema.Namespaces[0].Patterns[0]
+	assert.Equal(t, "http", p.Dialect)
+	assert.Equal(t, 11, p.NamePos.Column)
+	assert.Equal(t, 17, p.DialectPos.Column)
+}
+
+func TestLoadAnnotations(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace Tasks {\n  @go.name(\"Item\") deprecated\n  type Task {\n    @go.name(\"Name\") name: string\n  }\n}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	typ := res.Schema.Namespaces[0].Types[0]
+	require.Len(t, typ.Annotations, 1)
+	assert.Equal(t, "go.name", typ.Annotations[0].Name)
+	assert.Equal(t, 3, typ.Annotations[0].Pos.Line)
+	assert.Equal(t, 3, typ.Annotations[0].Pos.Column)
+	assert.Equal(t, "Item", *typ.Annotations[0].Args[0].String)
+	require.NotNil(t, typ.Deprecated)
+	assert.Empty(t, typ.Deprecated.Message)
+
+	// The name is not taken from the argument of the annotation.
+	field := typ.Fields[0]
+	assert.Equal(t, "Name", *field.Annotations[0].Args[0].String)
+	assert.Equal(t, 5, field.NamePos.Line)
+	assert.Equal(t, 22, field.NamePos.Column)
+}
+
+func TestLoadQuotedNamespace(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "billing.ufoc", `
+		version 1
+		namespace "billing-v2" {
+			option go.package = "billing"
+			option python.module = "acme.billing_v2"
+		}
+		namespace Tasks {}
+	`)
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	billing := res.Schema.Namespaces[0]
+	assert.Equal(t, "BillingV2", billing.Name)
+	assert.Equal(t, "billing-v2", billing.DisplayName)
+	assert.Equal(t, 3, billing.NamePos.Line)
+	assert.Equal(t, 13, billing.NamePos.Column)
+	require.Len(t, billing.Options, 2)
+	assert.Equal(t, "go.package", billing.Options[0].Key)
+	assert.Equal(t, "billing", billing.Options[0].Value)
+	assert.Equal(t, "billing", billing.GoPackage())
+	assert.Equal(t, "billing-v2", billing.TSModule())
+	assert.Equal(t, "acme.billing_v2", billing.PythonModule())
+
+	tasks := res.Schema.Namespaces[1]
+	assert.Equal(t, "Tasks", tasks.Name)
+	assert.Equal(t, "Tasks", tasks.DisplayName)
+}
+
+func TestLoadFileOptions(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "billing.ufoc", `
+		version 1
+		option wire.case = "snake"
+		namespace Billing {
+			namespace Invoices {}
+		}
+	`)
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	require.Len(t, res.Schema.Options, 1)
+	assert.Equal(t, "wire.case", res.Schema.Options[0].Key)
+	assert.Equal(t, "snake", res.Schema.Options[0].Value)
+	assert.Equal(t, 3, res.Schema.Options[0].Pos.Line)
+
+	invoices := res.Schema.Namespaces[0].Namespaces[0]
+	assert.Same(t, res.Schema, invoices.Schema)
+	assert.Equal(t, "snake", invoices.WireCase())
+}
+
+func TestLoadExternalDocs(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "docs/overview.md", "\n# Overview\n\n")
+	writeFile(t, dir, "docs/task.md", "A task.\n")
+	path := writeFile(t, dir, "tasks.ufoc", `
+		version 1
+		namespace Tasks {
+			""" ./docs/overview.md """
+
+			"""
+			docs/task.md
+			"""
+			type Task {
+				""" ./docs/task.md """
+				id: string
+			}
+
+			""" Inline documentation mentioning README.md """
+			const MaxRetries: int = 5
+		}
+	`)
+
+	res, err := Load(path)
+	require.NoError(t, err)
+	require.Empty(t, res.Diagnostics)
+
+	ns := res.Schema.Namespaces[0]
+	require.Len(t, ns.Docs, 1)
+	assert.True(t, ns.Docs[0].External())
+	assert.Equal(t, filepath.Join(dir, "docs", "overview.md"), ns.Docs[0].Source)
+	assert.Equal(t, "# Overview", ns.Docs[0].Text)
+
+	assert.Equal(t, "A task.", ns.Types[0].Doc.Text)
+	assert.Equal(t, "A task.", ns.Types[0].Fields[0].Doc.Text)
+
+	assert.False(t, ns.Consts[0].Doc.External())
+	assert.Equal(t, "Inline documentation mentioning README.md", ns.Consts[0].Doc.Text)
+
+	assert.Equal(t, []string{
+		path,
+		filepath.Join(dir, "docs", "overview.md"),
+		filepath.Join(dir, "docs", "task.md"),
+	}, res.Dependencies)
+}
+
+func TestLoadMissingExternalDoc(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\n\n\"\"\" ./missing.md \"\"\"\nnamespace Tasks {}\n")
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	require.Len(t, res.Diagnostics, 1)
+	d := res.Diagnostics[0]
+	assert.Equal(t, diagnostic.SeverityError, d.Severity)
+	assert.Equal(t, 3, d.Pos.Line)
+	assert.Equal(t, 1, d.Pos.Column)
+	assert.Contains(t, d.Message, `"./missing.md" not found`)
+
+	assert.Equal(t, []string{path, filepath.Join(dir, "missing.md")}, res.Dependencies)
+}
+
+func TestLoadSyntaxError(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "tasks.ufoc", "version 1\nnamespace {}")
+
+	_, err := Load(path)
+	require.Error(t, err)
+}
+
+func TestExternalDocPath(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+		ok       bool
+	}{
+		{`""" ./docs/tasks-overview.md """`, "./docs/tasks-overview.md", true},
+		{"\"\"\"\n\t../shared/intro.MD\n\t\"\"\"", "../shared/intro.MD", true},
+		{`"""docs/task.md"""`, "docs/task.md", true},
+		{`""" See ./docs/task.md """`, "", false},
+		{`""" /abs/task.md """`, "", false},
+		{`""" https://example.com/task.md """`, "", false},
+		{`""" ./docs/task.txt """`, "", false},
+		{`""" """`, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			path, ok := externalDocPath(tt.raw)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+/*******************
+* HELPER FUNCTIONS *
+*******************/
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadNestedNamespaces(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "billing.ufoc", `
+		version 1
+		namespace Billing {
+			namespace Invoices {
+				internal type Line { amount: int }
+			}
+			type Money { cents: int }
+		}
+	`)
+
+	res, err := Load(path)
+	require.NoError(t, err)
+
+	billing := res.Schema.Namespaces[0]
+	require.Len(t, res.Schema.Namespaces, 1)
+	require.Len(t, billing.Namespaces, 1)
+	require.Len(t, billing.Types, 1)
+
+	invoices := billing.Namespaces[0]
+	assert.Same(t, billing, invoices.Parent)
+	assert.Equal(t, "Billing.Invoices", invoices.Path())
+	assert.Equal(t, 4, invoices.NamePos.Line)
+	assert.True(t, invoices.Types[0].Internal)
+	assert.False(t, billing.Types[0].Internal)
+}
@@ -19,11 +19,12 @@ type File struct {
 }
 
 type FileChild struct {
-	Pos          lexer.Position `parser:""`
-	Docstring    *Docstring     `parser:"@@"`
-	Comment      *Comment       `parser:"| @@"`
-	BlockComment *BlockComment  `parser:"| @@"`
-	Namespace    *Namespace     `parser:"| @@"`
+	Pos          lexer.Position   `parser:""`
+	Docstring    *Docstring       `parser:"@@"`
+	Comment      *Comment         `parser:"| @@"`
+	BlockComment *BlockComment    `parser:"| @@"`
+	Option       *NamespaceOption `parser:"| @@"`
+	Namespace    *Namespace       `parser:"| @@"`
 }
 
 type Docstring struct {
@@ -34,44 +35,82 @@ type Docstring struct {
 
 type Namespace struct {
 	Pos       lexer.Position    `parser:""`
+	Tokens    []lexer.Token     `parser:""`
 	Docstring *string           `parser:"@Docstring?"`
-	Name      string            `parser:"'namespace' @Ident '{'"`
+	Name      string            `parser:"'namespace' ( @Ident | @String ) '{'"`
 	Children  []*NamespaceChild `parser:"@@* '}'"`
 }
 
 type NamespaceChild struct {
 	Pos lexer.Position `parser:""`
 
-	Docstring    *Docstring    `parser:"@@"`
-	Comment      *Comment      `parser:"| @@"`
-	BlockComment *BlockComment `parser:"| @@"`
-	Type         *TypeDef      `parser:"| @@"`
-	Enum         *EnumDef      `parser:"| @@"`
-	Const        *ConstDef     `parser:"| @@"`
-	Pattern      *PatternDef   `parser:"| @@"`
+	Docstring    *Docstring       `parser:"@@"`
+	Comment      *Comment         `parser:"| @@"`
+	BlockComment *BlockComment    `parser:"| @@"`
+	Option       *NamespaceOption `parser:"| @@"`
+	Type         *TypeDef         `parser:"| @@"`
+	Enum         *EnumDef         `parser:"| @@"`
+	Const        *ConstDef        `parser:"| @@"`
+	Pattern      *PatternDef      `parser:"| @@"`
+	Namespace    *Namespace       `parser:"| @@"`
 }
 
-type TypeDef struct {
-	Pos        lexer.Position `parser:""`
-	Docstring  *string        `parser:"@Docstring?"`
-	Deprecated *string        `parser:"( 'deprecated' ( '(' @String ')' )? )?"`
-	Name       string         `parser:"'type' @Ident '{'"`
-	Fields     []*Field       `parser:"@@* '}'"`
+// Annotation is an @name(args) annotation of a definition, a field or an enum
+// member, e.g. @go.name("ID").
+type Annotation struct {
+	Pos    lexer.Position `parser:""`
+	EndPos lexer.Position `parser:""`
+	Name   string         `parser:"'@' @( Ident | Keyword ) ( @'.' @( Ident | Keyword ) )*"`
+	Args   []*Value       `parser:"( '(' ( @@ ( ',' @@ )* ','? )? ')' )?"`
 }
 
+// Deprecated is the deprecated modifier of a definition, with its optional
+// message.
+type Deprecated struct {
+	Pos     lexer.Position `parser:""`
+	EndPos  lexer.Position `parser:""`
+	Message *string        `parser:"'deprecated' ( '(' @String ')' )?"`
+}
+
+type NamespaceOption struct {
+	Pos   lexer.Position `parser:""`
+	Key   string         `parser:"'option' @( Ident | Keyword ) ( @'.' @( Ident | Keyword ) )*"`
+	Value string         `parser:"'=' @String"`
+}
+
+type TypeDef struct {
+	Pos         lexer.Position `parser:""`
+	Tokens      []lexer.Token  `parser:""`
+	Docstring   *string        `parser:"@Docstring?"`
+	Annotations []*Annotation  `parser:"@@*"`
+	Deprecated  *Deprecated    `parser:"@@?"`
+	Internal    bool           `parser:"@'internal'?"`
+	Name        string         `parser:"'type' @Ident"`
+	TypeParams  []string       `parser:"( '<' @Ident ( ',' @Ident )* '>' )?"`
+	Fields      []*Field       `parser:"'{' @@* '}'"`
+}
+
+// Field is a field of a type body. A spread entry (...BaseEntity) is parsed
+// as a Field with only Spread set.
 type Field struct {
-	Pos       lexer.Position `parser:""`
-	Docstring *string        `parser:"@Docstring?"`
-	Name      string         `parser:"@Ident"`
-	Optional  bool           `parser:"@'?'?"`
-	Type      *TypeRef       `parser:"':' @@"`
+	Pos         lexer.Position `parser:""`
+	Tokens      []lexer.Token  `parser:""`
+	Spread      *TypeRef       `parser:"( '...' @@"`
+	Docstring   *string        `parser:"| @Docstring?"`
+	Annotations []*Annotation  `parser:"@@*"`
+	Name        string         `parser:"@( Ident | Keyword )"`
+	Optional    bool           `parser:"@'?'?"`
+	Type        *TypeRef       `parser:"':' @@"`
+	Nullable    bool           `parser:"@( '|' 'null' )?"`
+	Default     *Value         `parser:"( '=' @@ )? )"`
 }
 
 type TypeRef struct {
 	Pos lexer.Position `parser:""`
 
 	Inline *InlineType `parser:"  @@"`
-	Named  *string     `parser:"| @Ident"`
+	Named  *string     `parser:"| @Ident ( @'.' @Ident )*"`
+	Args   []*TypeRef  `parser:"  ( '<' @@ ( ',' @@ )* '>' )?"`
 	Array  bool        `parser:"  @( '[' ']' )?"`
 }
 
@@ -81,42 +120,81 @@ type InlineType struct {
 }
 
 type EnumDef struct {
-	Pos        lexer.Position `parser:""`
-	Docstring  *string        `parser:"@Docstring?"`
-	Deprecated *string        `parser:"( 'deprecated' ( '(' @String ')' )? )?"`
-	Name       string         `parser:"'enum' @Ident"`
-	BaseType   *string        `parser:"( ':' @Ident )?"`
-	Members    []*EnumMember  `parser:"'{' @@* '}'"`
+	Pos         lexer.Position `parser:""`
+	Tokens      []lexer.Token  `parser:""`
+	Docstring   *string        `parser:"@Docstring?"`
+	Annotations []*Annotation  `parser:"@@*"`
+	Deprecated  *Deprecated    `parser:"@@?"`
+	Internal    bool           `parser:"@'internal'?"`
+	Name        string         `parser:"'enum' @Ident"`
+	BaseType    *string        `parser:"( ':' @Ident )?"`
+	Members     []*EnumMember  `parser:"'{' @@* '}'"`
 }
 
 type EnumMember struct {
-	Pos       lexer.Position `parser:""`
-	Docstring *string        `parser:"@Docstring?"`
-	Name      string         `parser:"@Ident"`
-	Value     *Value         `parser:"( '=' @@ )?"`
+	Pos         lexer.Position `parser:""`
+	Tokens      []lexer.Token  `parser:""`
+	Docstring   *string        `parser:"@Docstring?"`
+	Annotations []*Annotation  `parser:"@@*"`
+	Name        string         `parser:"@Ident"`
+	Value       *Value         `parser:"( '=' @@ )?"`
 }
 
 type ConstDef struct {
-	Pos        lexer.Position `parser:""`
-	Docstring  *string        `parser:"@Docstring?"`
-	Deprecated *string        `parser:"( 'deprecated' ( '(' @String ')' )? )?"`
-	Name       string         `parser:"'const' @Ident"`
-	Type       *TypeRef       `parser:"':' @@"`
-	Value      *Value         `parser:"'=' @@"`
+	Pos         lexer.Position `parser:""`
+	Tokens      []lexer.Token  `parser:""`
+	Docstring   *string        `parser:"@Docstring?"`
+	Annotations []*Annotation  `parser:"@@*"`
+	Deprecated  *Deprecated    `parser:"@@?"`
+	Internal    bool           `parser:"@'internal'?"`
+	Name        string         `parser:"'const' @Ident"`
+	Type        *TypeRef       `parser:"':' @@"`
+	Value       *Value         `parser:"'=' @@"`
 }
 
 type PatternDef struct {
-	Pos        lexer.Position `parser:""`
-	Docstring  *string        `parser:"@Docstring?"`
-	Deprecated *string        `parser:"( 'deprecated' ( '(' @String ')' )? )?"`
-	Name       string         `parser:"'pattern' @Ident"`
-	Pattern    string         `parser:"'=' @String"`
-}
-
+	Pos         lexer.Position `parser:""`
+	Tokens      []lexer.Token  `parser:""`
+	Docstring   *string        `parser:"@Docstring?"`
+	Annotations []*Annotation  `parser:"@@*"`
+	Deprecated  *Deprecated    `parser:"@@?"`
+	Internal    bool           `parser:"@'internal'?"`
+	Name        string         `parser:"'pattern' @Ident"`
+	Dialect     *string        `parser:"( ':' @Ident )?"`
+	Pattern     *PatternString `parser:"'=' @String"`
+}
+
+// Value is a literal, a reference to a constant or enum member, or a constant
+// expression. Binary expressions are parsed as a chain of operands in source
+// order, e.g. 1 + 2 * 3 is {1, +, {2, *, {3}}}, and the loader applies the
+// operator precedence.
 type Value struct {
 	Pos lexer.Position `parser:""`
 
-	String *string `parser:"  @String"`
-	Number *string `parser:"| @Number"`
-	Ident  *string `parser:"| @Ident"`
+	String *string      `parser:"( @String"`
+	Number *string      `parser:"| @( ( '-' | '+' )? Number )"`
+	Ident  *string      `parser:"| @Ident ( @'.' @Ident )*"`
+	Array  *ArrayValue  `parser:"| @@"`
+	Object *ObjectValue `parser:"| @@"`
+	Group  *Value       `parser:"| '(' @@ ')'"`
+	Negate *Value       `parser:"| '-' @@ )"`
+
+	Op   string `parser:"( @( '+' | '-' | '*' | '/' | '%' )"`
+	Next *Value `parser:"  @@ )?"`
+}
+
+type ArrayValue struct {
+	Pos   lexer.Position `parser:""`
+	Items []*Value       `parser:"'[' ( @@ ( ',' @@ )* ','? )? ']'"`
+}
+
+type ObjectValue struct {
+	Pos     lexer.Position `parser:""`
+	Entries []*ObjectEntry `parser:"'{' ( @@ ( ',' @@ )* ','? )? '}'"`
+}
+
+type ObjectEntry struct {
+	Pos   lexer.Position `parser:""`
+	Key   string         `parser:"@( Ident | Keyword )"`
+	Value *Value         `parser:"':' @@"`
 }
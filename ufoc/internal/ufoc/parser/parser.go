@@ -8,5 +8,5 @@ import (
 var Parser = participle.MustBuild[File](
 	participle.Lexer(lexer.Def),
 	participle.Elide("Whitespace", "Newline", "BlankLine"),
-	participle.UseLookahead(2),
+	participle.UseLookahead(participle.MaxLookahead),
 )
@@ -58,6 +58,158 @@ func TestParserNamespaceWithDocstring(t *testing.T) {
 	})
 }
 
+func TestParserQuotedNamespace(t *testing.T) {
+	input := `
+		version 1
+		namespace "billing-v2" {
+			option go.package = "billingv2"
+			option ts.module = "billing/v2"
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "\"billing-v2\"",
+					Children: []*NamespaceChild{
+						{
+							Option: &NamespaceOption{
+								Key:   "go.package",
+								Value: "\"billingv2\"",
+							},
+						},
+						{
+							Option: &NamespaceOption{
+								Key:   "ts.module",
+								Value: "\"billing/v2\"",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserFileOptions(t *testing.T) {
+	input := `
+		version 1
+		option wire.case = "snake"
+		namespace Tasks {}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Option: &NamespaceOption{
+					Key:   "wire.case",
+					Value: "\"snake\"",
+				},
+			},
+			{
+				Namespace: &Namespace{Name: "Tasks"},
+			},
+		},
+	})
+}
+
+func TestParserReadmeExample(t *testing.T) {
+	input := `
+version 1
+
+"""
+This namespace defines all contracts for
+the asynchronous task management system.
+"""
+namespace "Tasks" {
+
+    """ Possible states for a Task. """
+    enum TaskStatus {
+        PENDING
+        RUNNING
+        COMPLETED
+        FAILED
+    }
+
+    """ Payload for creating a new task. """
+    type CreateTaskPayload {
+        """ The name of the task to execute. """
+        taskName: string
+
+        """ Arbitrary data for the task (stringified JSON). """
+        payload: string
+    }
+
+    """ Maximum number of retries for any task. """
+    const MaxRetries: int = 3
+
+    """
+    The NATS subject pattern for task-specific updates.
+    (Generates a builder function: BuildTaskUpdatesTopic(taskID string))
+    """
+    pattern TaskUpdatesTopic = "tasks.{taskID}.updates"
+
+}
+`
+
+	ast, err := Parser.ParseString("", input)
+	require.NoError(t, err)
+	require.Len(t, ast.Children, 1)
+	assert.Equal(t, "\"Tasks\"", ast.Children[0].Namespace.Name)
+	assert.Len(t, ast.Children[0].Namespace.Children, 4)
+}
+
+func TestParserNestedNamespace(t *testing.T) {
+	input := `
+		version 1
+		namespace Billing {
+			namespace Invoices {
+				internal type Line { amount: Billing.Money }
+				const DefaultStatus: Status = Billing.Invoices.Status.OPEN
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Billing",
+					Children: []*NamespaceChild{
+						{
+							Namespace: &Namespace{
+								Name: "Invoices",
+								Children: []*NamespaceChild{
+									{
+										Type: &TypeDef{
+											Internal: true,
+											Name:     "Line",
+											Fields: []*Field{
+												{Name: "amount", Type: &TypeRef{Named: strPtr("Billing.Money")}},
+											},
+										},
+									},
+									{
+										Const: &ConstDef{
+											Name:  "DefaultStatus",
+											Type:  &TypeRef{Named: strPtr("Status")},
+											Value: &Value{Ident: strPtr("Billing.Invoices.Status.OPEN")},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
 func TestParserSimpleType(t *testing.T) {
 	input := `
 		version 1
@@ -74,22 +226,500 @@ func TestParserSimpleType(t *testing.T) {
 		Children: []*FileChild{
 			{
 				Namespace: &Namespace{
-					Name: "Tasks",
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Type: &TypeDef{
+								Name: "Task",
+								Fields: []*Field{
+									{
+										Name: "id",
+										Type: &TypeRef{
+											Named: strPtr("string"),
+										},
+									},
+									{
+										Name: "status",
+										Type: &TypeRef{
+											Named: strPtr("int"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserOptionalField(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type Task {
+				id: string
+				tags?: string
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Type: &TypeDef{
+								Name: "Task",
+								Fields: []*Field{
+									{
+										Name: "id",
+										Type: &TypeRef{
+											Named: strPtr("string"),
+										},
+									},
+									{
+										Name:     "tags",
+										Optional: true,
+										Type: &TypeRef{
+											Named: strPtr("string"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserNullableField(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type TaskPatch {
+				note: string | null
+				tags?: string[] | null = null
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Type: &TypeDef{
+								Name: "TaskPatch",
+								Fields: []*Field{
+									{
+										Name:     "note",
+										Type:     &TypeRef{Named: strPtr("string")},
+										Nullable: true,
+									},
+									{
+										Name:     "tags",
+										Optional: true,
+										Type:     &TypeRef{Named: strPtr("string"), Array: true},
+										Nullable: true,
+										Default:  &Value{Ident: strPtr("null")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserArrayType(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type Task {
+				tags: string[]
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Type: &TypeDef{
+								Name: "Task",
+								Fields: []*Field{
+									{
+										Name: "tags",
+										Type: &TypeRef{
+											Named: strPtr("string"),
+											Array: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserTypeSpread(t *testing.T) {
+	input := `
+		version 1
+		namespace Users {
+			type User {
+				...BaseEntity
+				""" The user's email address """
+				email: string
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Users",
+					Children: []*NamespaceChild{
+						{
+							Type: &TypeDef{
+								Name: "User",
+								Fields: []*Field{
+									{
+										Spread: &TypeRef{
+											Named: strPtr("BaseEntity"),
+										},
+									},
+									{
+										Docstring: strPtr("\"\"\" The user's email address \"\"\""),
+										Name:      "email",
+										Type: &TypeRef{
+											Named: strPtr("string"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserGenericType(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type Page<T> {
+				items: T[]
+				total: int
+			}
+
+			type Envelope<K, V> {
+				key: K
+				value: V
+			}
+
+			type TaskList {
+				page: Page<Task>
+				pages: Page<Envelope<string, Task[]>>[]
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Type: &TypeDef{
+								Name:       "Page",
+								TypeParams: []string{"T"},
+								Fields: []*Field{
+									{
+										Name: "items",
+										Type: &TypeRef{
+											Named: strPtr("T"),
+											Array: true,
+										},
+									},
+									{
+										Name: "total",
+										Type: &TypeRef{
+											Named: strPtr("int"),
+										},
+									},
+								},
+							},
+						},
+						{
+							Type: &TypeDef{
+								Name:       "Envelope",
+								TypeParams: []string{"K", "V"},
+								Fields: []*Field{
+									{
+										Name: "key",
+										Type: &TypeRef{
+											Named: strPtr("K"),
+										},
+									},
+									{
+										Name: "value",
+										Type: &TypeRef{
+											Named: strPtr("V"),
+										},
+									},
+								},
+							},
+						},
+						{
+							Type: &TypeDef{
+								Name: "TaskList",
+								Fields: []*Field{
+									{
+										Name: "page",
+										Type: &TypeRef{
+											Named: strPtr("Page"),
+											Args: []*TypeRef{
+												{Named: strPtr("Task")},
+											},
+										},
+									},
+									{
+										Name: "pages",
+										Type: &TypeRef{
+											Named: strPtr("Page"),
+											Args: []*TypeRef{
+												{
+													Named: strPtr("Envelope"),
+													Args: []*TypeRef{
+														{Named: strPtr("string")},
+														{Named: strPtr("Task"), Array: true},
+													},
+												},
+											},
+											Array: true,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserFieldDefaults(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			type ListOptions {
+				pageSize: int = 25
+				status?: TaskStatus = PENDING
+				query: string = "*"
+				archived: bool = false
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Type: &TypeDef{
+								Name: "ListOptions",
+								Fields: []*Field{
+									{
+										Name:    "pageSize",
+										Type:    &TypeRef{Named: strPtr("int")},
+										Default: &Value{Number: strPtr("25")},
+									},
+									{
+										Name:     "status",
+										Optional: true,
+										Type:     &TypeRef{Named: strPtr("TaskStatus")},
+										Default:  &Value{Ident: strPtr("PENDING")},
+									},
+									{
+										Name:    "query",
+										Type:    &TypeRef{Named: strPtr("string")},
+										Default: &Value{String: strPtr(`"*"`)},
+									},
+									{
+										Name:    "archived",
+										Type:    &TypeRef{Named: strPtr("bool")},
+										Default: &Value{Ident: strPtr("false")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserEnum(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum TaskStatus {
+				PENDING
+				RUNNING
+				COMPLETED
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Enum: &EnumDef{
+								Name: "TaskStatus",
+								Members: []*EnumMember{
+									{Name: "PENDING"},
+									{Name: "RUNNING"},
+									{Name: "COMPLETED"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserEnumWithValues(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			enum ErrorCode: int {
+				UNKNOWN = 1
+				TIMEOUT = 100
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Enum: &EnumDef{
+								Name:     "ErrorCode",
+								BaseType: strPtr("int"),
+								Members: []*EnumMember{
+									{
+										Name: "UNKNOWN",
+										Value: &Value{
+											Number: strPtr("1"),
+										},
+									},
+									{
+										Name: "TIMEOUT",
+										Value: &Value{
+											Number: strPtr("100"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserUnderscoreIdentifiers(t *testing.T) {
+	input := `
+		version 1
+		namespace Payments {
+			enum PaymentMethod: string {
+				CREDIT_CARD = "credit_card"
+				BANK_TRANSFER = "bank_transfer"
+			}
+
+			type legacy_payment {
+				payment_method: PaymentMethod
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Payments",
 					Children: []*NamespaceChild{
 						{
-							Type: &TypeDef{
-								Name: "Task",
-								Fields: []*Field{
+							Enum: &EnumDef{
+								Name:     "PaymentMethod",
+								BaseType: strPtr("string"),
+								Members: []*EnumMember{
 									{
-										Name: "id",
-										Type: &TypeRef{
-											Named: strPtr("string"),
+										Name: "CREDIT_CARD",
+										Value: &Value{
+											String: strPtr("\"credit_card\""),
 										},
 									},
 									{
-										Name: "status",
+										Name: "BANK_TRANSFER",
+										Value: &Value{
+											String: strPtr("\"bank_transfer\""),
+										},
+									},
+								},
+							},
+						},
+						{
+							Type: &TypeDef{
+								Name: "legacy_payment",
+								Fields: []*Field{
+									{
+										Name: "payment_method",
 										Type: &TypeRef{
-											Named: strPtr("int"),
+											Named: strPtr("PaymentMethod"),
 										},
 									},
 								},
@@ -102,13 +732,18 @@ func TestParserSimpleType(t *testing.T) {
 	})
 }
 
-func TestParserOptionalField(t *testing.T) {
+func TestParserKeywordFieldNames(t *testing.T) {
 	input := `
 		version 1
-		namespace Tasks {
-			type Task {
-				id: string
-				tags?: string
+		namespace Events {
+			type Event {
+				type: string
+				version?: int
+				namespace: string
+				payload: {
+					type: string
+					deprecated: bool
+				}
 			}
 		}
 	`
@@ -118,25 +753,52 @@ func TestParserOptionalField(t *testing.T) {
 		Children: []*FileChild{
 			{
 				Namespace: &Namespace{
-					Name: "Tasks",
+					Name: "Events",
 					Children: []*NamespaceChild{
 						{
 							Type: &TypeDef{
-								Name: "Task",
+								Name: "Event",
 								Fields: []*Field{
 									{
-										Name: "id",
+										Name: "type",
 										Type: &TypeRef{
 											Named: strPtr("string"),
 										},
 									},
 									{
-										Name:     "tags",
+										Name:     "version",
 										Optional: true,
+										Type: &TypeRef{
+											Named: strPtr("int"),
+										},
+									},
+									{
+										Name: "namespace",
 										Type: &TypeRef{
 											Named: strPtr("string"),
 										},
 									},
+									{
+										Name: "payload",
+										Type: &TypeRef{
+											Inline: &InlineType{
+												Fields: []*Field{
+													{
+														Name: "type",
+														Type: &TypeRef{
+															Named: strPtr("string"),
+														},
+													},
+													{
+														Name: "deprecated",
+														Type: &TypeRef{
+															Named: strPtr("bool"),
+														},
+													},
+												},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -147,13 +809,23 @@ func TestParserOptionalField(t *testing.T) {
 	})
 }
 
-func TestParserArrayType(t *testing.T) {
+func TestParserKeywordTypeNameIsInvalid(t *testing.T) {
+	input := `
+		version 1
+		namespace Events {
+			type type {}
+		}
+	`
+
+	_, err := Parser.ParseString("", input)
+	require.Error(t, err)
+}
+
+func TestParserConst(t *testing.T) {
 	input := `
 		version 1
 		namespace Tasks {
-			type Task {
-				tags: string[]
-			}
+			const MaxRetries: int = 5
 		}
 	`
 
@@ -165,16 +837,13 @@ func TestParserArrayType(t *testing.T) {
 					Name: "Tasks",
 					Children: []*NamespaceChild{
 						{
-							Type: &TypeDef{
-								Name: "Task",
-								Fields: []*Field{
-									{
-										Name: "tags",
-										Type: &TypeRef{
-											Named: strPtr("string"),
-											Array: true,
-										},
-									},
+							Const: &ConstDef{
+								Name: "MaxRetries",
+								Type: &TypeRef{
+									Named: strPtr("int"),
+								},
+								Value: &Value{
+									Number: strPtr("5"),
 								},
 							},
 						},
@@ -185,14 +854,15 @@ func TestParserArrayType(t *testing.T) {
 	})
 }
 
-func TestParserEnum(t *testing.T) {
+func TestParserStructuredConst(t *testing.T) {
 	input := `
 		version 1
 		namespace Tasks {
-			enum TaskStatus {
-				PENDING
-				RUNNING
-				COMPLETED
+			const AllowedRegions: string[] = ["eu", "us",]
+			const DefaultRetryPolicy: RetryPolicy = {
+				maxAttempts: 3,
+				backoff: { type: "exponential" },
+				codes: []
 			}
 		}
 	`
@@ -205,12 +875,42 @@ func TestParserEnum(t *testing.T) {
 					Name: "Tasks",
 					Children: []*NamespaceChild{
 						{
-							Enum: &EnumDef{
-								Name: "TaskStatus",
-								Members: []*EnumMember{
-									{Name: "PENDING"},
-									{Name: "RUNNING"},
-									{Name: "COMPLETED"},
+							Const: &ConstDef{
+								Name: "AllowedRegions",
+								Type: &TypeRef{
+									Named: strPtr("string"),
+									Array: true,
+								},
+								Value: &Value{
+									Array: &ArrayValue{
+										Items: []*Value{
+											{String: strPtr(`"eu"`)},
+											{String: strPtr(`"us"`)},
+										},
+									},
+								},
+							},
+						},
+						{
+							Const: &ConstDef{
+								Name: "DefaultRetryPolicy",
+								Type: &TypeRef{
+									Named: strPtr("RetryPolicy"),
+								},
+								Value: &Value{
+									Object: &ObjectValue{
+										Entries: []*ObjectEntry{
+											{Key: "maxAttempts", Value: &Value{Number: strPtr("3")}},
+											{Key: "backoff", Value: &Value{
+												Object: &ObjectValue{
+													Entries: []*ObjectEntry{
+														{Key: "type", Value: &Value{String: strPtr(`"exponential"`)}},
+													},
+												},
+											}},
+											{Key: "codes", Value: &Value{Array: &ArrayValue{}}},
+										},
+									},
 								},
 							},
 						},
@@ -221,14 +921,13 @@ func TestParserEnum(t *testing.T) {
 	})
 }
 
-func TestParserEnumWithValues(t *testing.T) {
+func TestParserConstExpression(t *testing.T) {
 	input := `
 		version 1
 		namespace Tasks {
-			enum ErrorCode: int {
-				UNKNOWN = 1
-				TIMEOUT = 100
-			}
+			const MaxBackoffMs: int = BaseBackoffMs * (2 + -1) - -3
+			const Status: TaskStatus = TaskStatus.PENDING
+			const Offset: int = -Base
 		}
 	`
 
@@ -240,25 +939,38 @@ func TestParserEnumWithValues(t *testing.T) {
 					Name: "Tasks",
 					Children: []*NamespaceChild{
 						{
-							Enum: &EnumDef{
-								Name:     "ErrorCode",
-								BaseType: strPtr("int"),
-								Members: []*EnumMember{
-									{
-										Name: "UNKNOWN",
-										Value: &Value{
-											Number: strPtr("1"),
-										},
-									},
-									{
-										Name: "TIMEOUT",
-										Value: &Value{
-											Number: strPtr("100"),
+							Const: &ConstDef{
+								Name: "MaxBackoffMs",
+								Type: &TypeRef{Named: strPtr("int")},
+								Value: &Value{
+									Ident: strPtr("BaseBackoffMs"),
+									Op:    "*",
+									Next: &Value{
+										Group: &Value{
+											Number: strPtr("2"),
+											Op:     "+",
+											Next:   &Value{Number: strPtr("-1")},
 										},
+										Op:   "-",
+										Next: &Value{Number: strPtr("-3")},
 									},
 								},
 							},
 						},
+						{
+							Const: &ConstDef{
+								Name:  "Status",
+								Type:  &TypeRef{Named: strPtr("TaskStatus")},
+								Value: &Value{Ident: strPtr("TaskStatus.PENDING")},
+							},
+						},
+						{
+							Const: &ConstDef{
+								Name:  "Offset",
+								Type:  &TypeRef{Named: strPtr("int")},
+								Value: &Value{Negate: &Value{Ident: strPtr("Base")}},
+							},
+						},
 					},
 				},
 			},
@@ -266,11 +978,11 @@ func TestParserEnumWithValues(t *testing.T) {
 	})
 }
 
-func TestParserConst(t *testing.T) {
+func TestParserPattern(t *testing.T) {
 	input := `
 		version 1
 		namespace Tasks {
-			const MaxRetries: int = 5
+			pattern TaskTopic = "{ns}.{taskId}.updates"
 		}
 	`
 
@@ -282,13 +994,45 @@ func TestParserConst(t *testing.T) {
 					Name: "Tasks",
 					Children: []*NamespaceChild{
 						{
-							Const: &ConstDef{
-								Name: "MaxRetries",
-								Type: &TypeRef{
-									Named: strPtr("int"),
-								},
-								Value: &Value{
-									Number: strPtr("5"),
+							Pattern: &PatternDef{
+								Name:    "TaskTopic",
+								Pattern: taskTopicPattern(),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserPatternPlaceholders(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			pattern TaskShardTopic = "tasks.{ taskId : uuid }.{shard:int}-\u007bx\u007d"
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Pattern: &PatternDef{
+								Name: "TaskShardTopic",
+								Pattern: &PatternString{
+									Raw: `"tasks.{ taskId : uuid }.{shard:int}-\u007bx\u007d"`,
+									Segments: []*PatternSegment{
+										{Literal: "tasks.", Offset: 1},
+										{Placeholder: &Placeholder{Name: "taskId", NameOffset: 9, Type: "uuid", TypeOffset: 18}},
+										{Literal: ".", Offset: 24},
+										{Placeholder: &Placeholder{Name: "shard", NameOffset: 26, Type: "int", TypeOffset: 32}},
+										{Literal: "-{x}", Offset: 36},
+									},
 								},
 							},
 						},
@@ -299,14 +1043,15 @@ func TestParserConst(t *testing.T) {
 	})
 }
 
-func TestParserPattern(t *testing.T) {
+func TestParserPatternDialect(t *testing.T) {
 	input := `
 		version 1
 		namespace Tasks {
-			pattern TaskTopic = "{ns}.{taskId}.updates"
+			pattern TaskRoute: http = "/tasks"
 		}
 	`
 
+	dialect := "http"
 	assertAST(t, input, &File{
 		Version: 1,
 		Children: []*FileChild{
@@ -316,8 +1061,12 @@ func TestParserPattern(t *testing.T) {
 					Children: []*NamespaceChild{
 						{
 							Pattern: &PatternDef{
-								Name:    "TaskTopic",
-								Pattern: "\"{ns}.{taskId}.updates\"",
+								Name:    "TaskRoute",
+								Dialect: &dialect,
+								Pattern: &PatternString{
+									Raw:      `"/tasks"`,
+									Segments: []*PatternSegment{{Literal: "/tasks", Offset: 1}},
+								},
 							},
 						},
 					},
@@ -327,6 +1076,30 @@ func TestParserPattern(t *testing.T) {
 	})
 }
 
+func TestParserInvalidPatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		message string
+	}{
+		{"unterminated", `"tasks.{taskId"`, `unterminated placeholder in pattern "tasks.{taskId"`},
+		{"unexpected brace", `"tasks.}"`, `unexpected "}" in pattern "tasks.}"`},
+		{"empty", `"tasks.{}"`, `invalid placeholder {}, expected {name} or {name: Type}`},
+		{"invalid name", `"tasks.{task-id}"`, `invalid placeholder {task-id}, expected {name} or {name: Type}`},
+		{"missing type", `"tasks.{taskId:}"`, `invalid placeholder {taskId:}, expected {name} or {name: Type}`},
+		{"array type", `"tasks.{ids: uuid[]}"`, `invalid placeholder {ids: uuid[]}, expected {name} or {name: Type}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "version 1\nnamespace Tasks {\npattern Topic = " + tt.pattern + "\n}"
+			_, err := Parser.ParseString("", input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.message)
+		})
+	}
+}
+
 func TestParserDeprecated(t *testing.T) {
 	input := `
 		version 1
@@ -347,7 +1120,7 @@ func TestParserDeprecated(t *testing.T) {
 					Children: []*NamespaceChild{
 						{
 							Type: &TypeDef{
-								Deprecated: strPtr("\"Use NewTask instead\""),
+								Deprecated: &Deprecated{Message: strPtr("\"Use NewTask instead\"")},
 								Name:       "OldTask",
 								Fields: []*Field{
 									{
@@ -366,6 +1139,112 @@ func TestParserDeprecated(t *testing.T) {
 	})
 }
 
+func TestParserDeprecatedDefinitions(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			deprecated enum OldStatus { PENDING }
+			deprecated("Use ErrorQueue instead")
+			const FailureQueue: string = "tasks.failed"
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Enum: &EnumDef{
+								Deprecated: &Deprecated{},
+								Name:       "OldStatus",
+								Members:    []*EnumMember{{Name: "PENDING"}},
+							},
+						},
+						{
+							Const: &ConstDef{
+								Deprecated: &Deprecated{Message: strPtr(`"Use ErrorQueue instead"`)},
+								Name:       "FailureQueue",
+								Type:       &TypeRef{Named: strPtr("string")},
+								Value:      &Value{String: strPtr(`"tasks.failed"`)},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestParserAnnotations(t *testing.T) {
+	input := `
+		version 1
+		namespace Tasks {
+			@go.name("TaskItem")
+			@audit
+			deprecated
+			type Task {
+				@json.name("task_id") @db.column("id", { primary: true })
+				id: string
+			}
+
+			enum Status {
+				@ts.name("Open")
+				OPEN
+			}
+		}
+	`
+
+	assertAST(t, input, &File{
+		Version: 1,
+		Children: []*FileChild{
+			{
+				Namespace: &Namespace{
+					Name: "Tasks",
+					Children: []*NamespaceChild{
+						{
+							Type: &TypeDef{
+								Annotations: []*Annotation{
+									{Name: "go.name", Args: []*Value{{String: strPtr(`"TaskItem"`)}}},
+									{Name: "audit"},
+								},
+								Deprecated: &Deprecated{},
+								Name:       "Task",
+								Fields: []*Field{
+									{
+										Annotations: []*Annotation{
+											{Name: "json.name", Args: []*Value{{String: strPtr(`"task_id"`)}}},
+											{Name: "db.column", Args: []*Value{
+												{String: strPtr(`"id"`)},
+												{Object: &ObjectValue{Entries: []*ObjectEntry{{Key: "primary", Value: &Value{Ident: strPtr("true")}}}}},
+											}},
+										},
+										Name: "id",
+										Type: &TypeRef{Named: strPtr("string")},
+									},
+								},
+							},
+						},
+						{
+							Enum: &EnumDef{
+								Name: "Status",
+								Members: []*EnumMember{
+									{
+										Annotations: []*Annotation{{Name: "ts.name", Args: []*Value{{String: strPtr(`"Open"`)}}}},
+										Name:        "OPEN",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
 func TestParserCompleteExample(t *testing.T) {
 	input := `
 		version 1
@@ -553,7 +1432,7 @@ func TestParserCompleteExample(t *testing.T) {
 							Pattern: &PatternDef{
 								Docstring: strPtr("\"\"\"\n\t\t\tTopic for updates on a specific task.\n\t\t\t\"\"\""),
 								Name:      "TaskUpdatesTopic",
-								Pattern:   "\"{ns}.{taskId}.updates\"",
+								Pattern:   taskTopicPattern(),
 							},
 						},
 					},
@@ -584,6 +1463,18 @@ func assertAST(t *testing.T, input string, expected *File) {
 	assert.Equal(t, expected, ast)
 }
 
+func taskTopicPattern() *PatternString {
+	return &PatternString{
+		Raw: `"{ns}.{taskId}.updates"`,
+		Segments: []*PatternSegment{
+			{Placeholder: &Placeholder{Name: "ns", NameOffset: 2}},
+			{Literal: ".", Offset: 5},
+			{Placeholder: &Placeholder{Name: "taskId", NameOffset: 7}},
+			{Literal: ".updates", Offset: 14},
+		},
+	}
+}
+
 func stripPositions(v any) {
 	if v == nil {
 		return
@@ -606,7 +1497,12 @@ func stripPositions(v any) {
 		field := val.Field(i)
 		fieldType := typ.Field(i)
 
-		if fieldType.Name == "Pos" && fieldType.Type == reflect.TypeOf(lexer.Position{}) {
+		if (fieldType.Name == "Pos" || fieldType.Name == "EndPos") && fieldType.Type == reflect.TypeOf(lexer.Position{}) {
+			field.Set(reflect.Zero(fieldType.Type))
+			continue
+		}
+
+		if fieldType.Name == "Tokens" && fieldType.Type == reflect.TypeOf([]lexer.Token{}) {
 			field.Set(reflect.Zero(fieldType.Type))
 			continue
 		}
@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PatternString is the string of a pattern definition, split into its literal
+// segments and placeholders when it is parsed.
+type PatternString struct {
+	// Raw is the string exactly as written in the source, quotes included.
+	Raw      string
+	Segments []*PatternSegment
+}
+
+// PatternSegment is either a literal part of a pattern or a placeholder.
+type PatternSegment struct {
+	// Literal is the unescaped literal text, empty for placeholders, and
+	// Offset its byte offset in Raw.
+	Literal     string
+	Offset      int
+	Placeholder *Placeholder
+}
+
+// Placeholder is a {name} or {name: Type} placeholder of a pattern.
+type Placeholder struct {
+	// Name is the placeholder name and NameOffset its byte offset in Raw.
+	Name       string
+	NameOffset int
+	// Type is the placeholder type, empty when omitted, and TypeOffset its byte
+	// offset in Raw.
+	Type       string
+	TypeOffset int
+}
+
+var placeholderIdent = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Capture implements participle.Capture.
+func (p *PatternString) Capture(values []string) error {
+	p.Raw = strings.Join(values, "")
+	p.Segments = nil
+
+	var literal strings.Builder
+	start := 0
+	flush := func() {
+		if literal.Len() > 0 {
+			p.Segments = append(p.Segments, &PatternSegment{Literal: literal.String(), Offset: start})
+			literal.Reset()
+		}
+	}
+
+	// Offsets are relative to Raw, which starts with the opening quote.
+	end := len(p.Raw) - 1
+	for i := 1; i < end; {
+		if literal.Len() == 0 {
+			start = i
+		}
+		switch c := p.Raw[i]; c {
+		case '\\':
+			n := 2
+			if p.Raw[i+1] == 'u' {
+				n = 6
+			}
+			var s string
+			if err := json.Unmarshal([]byte(`"`+p.Raw[i:i+n]+`"`), &s); err != nil {
+				return err
+			}
+			// Escaped braces are literal text, e.g. "\u007b".
+			literal.WriteString(s)
+			i += n
+		case '{':
+			closing := strings.IndexByte(p.Raw[i:end], '}')
+			if closing < 0 {
+				return fmt.Errorf("unterminated placeholder in pattern %s", p.Raw)
+			}
+			ph, err := parsePlaceholder(p.Raw[i+1:i+closing], i+1)
+			if err != nil {
+				return err
+			}
+			flush()
+			p.Segments = append(p.Segments, &PatternSegment{Placeholder: ph})
+			i += closing + 1
+		case '}':
+			return fmt.Errorf("unexpected \"}\" in pattern %s", p.Raw)
+		default:
+			literal.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+
+	return nil
+}
+
+// parsePlaceholder parses the text between the braces of a placeholder, which
+// starts at offset in the pattern.
+func parsePlaceholder(text string, offset int) (*Placeholder, error) {
+	name, typ, typed := strings.Cut(text, ":")
+	ph := &Placeholder{
+		Name:       strings.TrimSpace(name),
+		NameOffset: offset + len(name) - len(strings.TrimLeft(name, " \t")),
+	}
+	if !placeholderIdent.MatchString(ph.Name) {
+		return nil, fmt.Errorf("invalid placeholder {%s}, expected {name} or {name: Type}", text)
+	}
+	if typed {
+		ph.Type = strings.TrimSpace(typ)
+		ph.TypeOffset = offset + len(name) + 1 + len(typ) - len(strings.TrimLeft(typ, " \t"))
+		if !placeholderIdent.MatchString(ph.Type) {
+			return nil, fmt.Errorf("invalid placeholder {%s}, expected {name} or {name: Type}", text)
+		}
+	}
+	return ph, nil
+}
@@ -0,0 +1,86 @@
+// Package reserved knows the reserved words of every generator target
+// language. DSL keywords are valid field names (they are contextual keywords),
+// so generators escape names that collide with a word reserved in their
+// target language before using them as identifiers.
+package reserved
+
+// Language is the set of reserved words of a target language together with
+// its escaping convention.
+type Language struct {
+	Name   string
+	words  map[string]bool
+	escape func(string) string
+}
+
+// IsReserved reports whether name cannot be used as an identifier as is.
+func (l Language) IsReserved(name string) bool {
+	return l.words[name]
+}
+
+// Escape returns name unchanged unless it is reserved, in which case it
+// returns the escaped identifier, e.g. type_ in Go or r#type in Rust.
+func (l Language) Escape(name string) string {
+	if !l.IsReserved(name) {
+		return name
+	}
+	return l.escape(name)
+}
+
+func suffix(name string) string {
+	return name + "_"
+}
+
+func newLanguage(name string, escape func(string) string, words ...string) Language {
+	l := Language{Name: name, words: make(map[string]bool, len(words)), escape: escape}
+	for _, w := range words {
+		l.words[w] = true
+	}
+	return l
+}
+
+// Go keywords, escaped with a trailing underscore.
+var Go = newLanguage("go", suffix,
+	"break", "case", "chan", "const", "continue", "default", "defer", "else",
+	"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+	"map", "package", "range", "return", "select", "struct", "switch", "type",
+	"var",
+)
+
+// TypeScript reserved and strict mode reserved words, escaped with a trailing
+// underscore. Property names may be reserved words in TypeScript, so this only
+// applies to identifiers like variables and function parameters.
+var TypeScript = newLanguage("typescript", suffix,
+	"await", "break", "case", "catch", "class", "const", "continue", "debugger",
+	"default", "delete", "do", "else", "enum", "export", "extends", "false",
+	"finally", "for", "function", "if", "implements", "import", "in",
+	"instanceof", "interface", "let", "new", "null", "package", "private",
+	"protected", "public", "return", "static", "super", "switch", "this",
+	"throw", "true", "try", "typeof", "var", "void", "while", "with", "yield",
+)
+
+// Python keywords, escaped with a trailing underscore as recommended by PEP 8.
+var Python = newLanguage("python", suffix,
+	"False", "None", "True", "and", "as", "assert", "async", "await", "break",
+	"class", "continue", "def", "del", "elif", "else", "except", "finally",
+	"for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal",
+	"not", "or", "pass", "raise", "return", "try", "while", "with", "yield",
+)
+
+// Rust strict and reserved keywords, escaped as raw identifiers. The keywords
+// that cannot be raw identifiers get a trailing underscore instead.
+var Rust = newLanguage("rust", func(name string) string {
+	switch name {
+	case "crate", "self", "Self", "super":
+		return suffix(name)
+	default:
+		return "r#" + name
+	}
+},
+	"as", "async", "await", "break", "const", "continue", "crate", "dyn",
+	"else", "enum", "extern", "false", "fn", "for", "if", "impl", "in", "let",
+	"loop", "match", "mod", "move", "mut", "pub", "ref", "return", "self",
+	"Self", "static", "struct", "super", "trait", "true", "type", "unsafe",
+	"use", "where", "while", "abstract", "become", "box", "do", "final",
+	"gen", "macro", "override", "priv", "try", "typeof", "unsized", "virtual",
+	"yield",
+)
@@ -0,0 +1,42 @@
+package reserved
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		lang     Language
+		input    string
+		expected string
+	}{
+		{Go, "type", "type_"},
+		{Go, "version", "version"},
+		{Go, "namespace", "namespace"},
+		{TypeScript, "type", "type"},
+		{TypeScript, "enum", "enum_"},
+		{TypeScript, "default", "default_"},
+		{Python, "from", "from_"},
+		{Python, "type", "type"},
+		{Python, "None", "None_"},
+		{Rust, "type", "r#type"},
+		{Rust, "enum", "r#enum"},
+		{Rust, "self", "self_"},
+		{Rust, "version", "version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang.Name+"/"+tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.lang.Escape(tt.input))
+		})
+	}
+}
+
+func TestIsReserved(t *testing.T) {
+	assert.True(t, Go.IsReserved("func"))
+	assert.False(t, Go.IsReserved("Type"))
+	assert.True(t, Rust.IsReserved("Self"))
+	assert.False(t, Python.IsReserved("none"))
+}
@@ -0,0 +1,325 @@
+// Package validate checks JSON documents against the types of a contract,
+// e.g. messages captured from a broker or received by a gateway. Every
+// violation is reported with the JSON pointer of the invalid value and the
+// position in the contract of the field it violates.
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/uforg/ufocontract/internal/ufoc/analyzer"
+	"github.com/uforg/ufocontract/internal/ufoc/diagnostic"
+	"github.com/uforg/ufocontract/internal/ufoc/ir"
+	"github.com/uforg/ufocontract/internal/ufoc/loader"
+)
+
+// Contract is a contract documents are validated against.
+type Contract struct {
+	schema      *ir.Schema
+	fieldOwners map[*ir.Field]*ir.Namespace
+	enumOwners  map[*ir.Enum]*ir.Namespace
+}
+
+// Load loads and analyzes the contract file at path. It fails when the
+// contract has errors, listing them.
+func Load(path string) (*Contract, error) {
+	res, err := loader.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return analyze(path, res)
+}
+
+// LoadBytes is like Load but uses src as the contract instead of reading it,
+// e.g. a contract embedded in the program or fetched from a registry. path
+// names the contract in errors, and external documentation files are
+// resolved relative to its directory.
+func LoadBytes(path string, src []byte) (*Contract, error) {
+	res, err := loader.LoadBytes(path, src)
+	if err != nil {
+		return nil, err
+	}
+	return analyze(path, res)
+}
+
+func analyze(path string, res *loader.Result) (*Contract, error) {
+	diags := res.Diagnostics
+	if !diagnostic.HasErrors(diags) {
+		diags = append(diags, analyzer.Analyze(res.Schema, analyzer.Config{})...)
+	}
+
+	var errs []string
+	for _, d := range diags {
+		if d.Severity == diagnostic.SeverityError {
+			errs = append(errs, d.String())
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s has errors:\n%s", path, strings.Join(errs, "\n"))
+	}
+	return &Contract{
+		schema:      res.Schema,
+		fieldOwners: res.Schema.FieldNamespaces(),
+		enumOwners:  res.Schema.EnumNamespaces(),
+	}, nil
+}
+
+// Validator returns the validator of the type with the qualified name, e.g.
+// Tasks.Task.
+func (c *Contract) Validator(name string) (*Validator, error) {
+	ns, t := c.schema.LookupType(name)
+	switch {
+	case t == nil && !strings.Contains(name, "."):
+		return nil, fmt.Errorf("type %q must be qualified by its namespace, e.g. Tasks.%s", name, name)
+	case t == nil:
+		return nil, fmt.Errorf("unknown type %q", name)
+	case t.Generic():
+		return nil, fmt.Errorf("type %q is generic, validate against a type instantiating it instead", name)
+	}
+	return &Validator{contract: c, ns: ns, typ: t}, nil
+}
+
+// Validator validates documents against a type of a contract. It is safe for
+// concurrent use.
+type Validator struct {
+	contract *Contract
+	ns       *ir.Namespace
+	typ      *ir.Type
+}
+
+// Violation is a value of a document that does not follow the contract.
+type Violation struct {
+	// Pointer is the JSON pointer (RFC 6901) of the value, or of the place
+	// of a missing field. It is empty for the document itself.
+	Pointer string
+	Message string
+	// Pos is the position of the violated field in the contract. For the
+	// document itself and for unknown fields of an object it is the position
+	// of the type or field holding the object.
+	Pos lexer.Position
+}
+
+func (v Violation) String() string {
+	if v.Pointer == "" {
+		return fmt.Sprintf("%s (%s)", v.Message, v.Pos)
+	}
+	return fmt.Sprintf("%s: %s (%s)", v.Pointer, v.Message, v.Pos)
+}
+
+// Validate validates a JSON document. It returns the violations found, in
+// the order of the fields in the contract, and fails only when data is not a
+// single JSON value.
+func (v *Validator) Validate(data []byte) ([]Violation, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid JSON: unexpected data after the document")
+	}
+
+	c := &checker{contract: v.contract}
+	c.object(v.ns, v.typ.AllFields(), doc, "", v.typ.Pos)
+	return c.violations, nil
+}
+
+// checker collects the violations of a document.
+type checker struct {
+	contract   *Contract
+	violations []Violation
+}
+
+func (c *checker) report(pointer string, pos lexer.Position, format string, args ...any) {
+	c.violations = append(c.violations, Violation{Pointer: pointer, Message: fmt.Sprintf(format, args...), Pos: pos})
+}
+
+// object checks that v is an object with the fields of ns, and nothing else.
+// pos is the position of the type or field holding the object.
+func (c *checker) object(ns *ir.Namespace, fields []*ir.Field, v any, pointer string, pos lexer.Position) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		c.report(pointer, pos, "expected object, got %s", kind(v))
+		return
+	}
+
+	known := map[string]bool{}
+	for _, f := range fields {
+		owner, ok := c.contract.fieldOwners[f]
+		if !ok {
+			owner = ns
+		}
+		name := owner.WireName(f)
+		known[name] = true
+
+		fieldPointer := pointer + "/" + escape(name)
+		fv, ok := obj[name]
+		switch {
+		case !ok && !f.Optional:
+			c.report(fieldPointer, f.Pos, "missing required field")
+		case !ok:
+		case fv == nil && f.Nullable:
+		default:
+			c.value(owner, f.Type, fv, fieldPointer, f.Pos)
+		}
+	}
+
+	var unknown []string
+	for name := range obj {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	slices.Sort(unknown)
+	for _, name := range unknown {
+		c.report(pointer+"/"+escape(name), pos, "unknown field %q", name)
+	}
+}
+
+func (c *checker) value(ns *ir.Namespace, ref *ir.TypeRef, v any, pointer string, pos lexer.Position) {
+	if ref.Array {
+		items, ok := v.([]any)
+		if !ok {
+			c.report(pointer, pos, "expected array, got %s", kind(v))
+			return
+		}
+		item := *ref
+		item.Array = false
+		for i, iv := range items {
+			c.value(ns, &item, iv, pointer+"/"+strconv.Itoa(i), pos)
+		}
+		return
+	}
+
+	switch {
+	case ref.Inline():
+		c.object(ns, ref.Fields, v, pointer, pos)
+	case ref.Instance != nil:
+		c.object(ns, ref.Instance.Type.AllFields(), v, pointer, pos)
+	case ref.Type != nil:
+		c.object(ns, ref.Type.AllFields(), v, pointer, pos)
+	case ref.Enum != nil:
+		c.enum(ref.Enum, v, pointer, pos)
+	case ref.Primitive != "":
+		c.primitive(ref.Primitive, v, pointer, pos)
+	}
+}
+
+func (c *checker) enum(e *ir.Enum, v any, pointer string, pos lexer.Position) {
+	if e.Flags() {
+		c.flags(e, v, pointer, pos)
+		return
+	}
+
+	expected, wire, ok := "string", "", false
+	if e.BaseType == string(ir.Int) {
+		var n json.Number
+		n, ok = v.(json.Number)
+		expected, wire = "integer", n.String()
+	} else {
+		wire, ok = v.(string)
+	}
+	if !ok {
+		c.report(pointer, pos, "expected %s, got %s", expected, kind(v))
+		return
+	}
+	if _, err := e.Decode(wire); err != nil {
+		c.report(pointer, pos, "%s", err)
+	}
+}
+
+// flags checks a flags enum value, encoded as a mask or as an array of
+// member names depending on the json.flags encoding of the enum.
+func (c *checker) flags(e *ir.Enum, v any, pointer string, pos lexer.Position) {
+	ns, ok := c.contract.enumOwners[e]
+	if ok && ns.FlagsEncoding(e) == ir.FlagsNames {
+		names, ok := v.([]any)
+		if !ok {
+			c.report(pointer, pos, "expected array, got %s", kind(v))
+			return
+		}
+		for i, nv := range names {
+			itemPointer := pointer + "/" + strconv.Itoa(i)
+			name, ok := nv.(string)
+			if !ok {
+				c.report(itemPointer, pos, "expected string, got %s", kind(nv))
+				continue
+			}
+			if _, err := e.FlagMask([]string{name}); err != nil {
+				c.report(itemPointer, pos, "%s", err)
+			}
+		}
+		return
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		c.report(pointer, pos, "expected integer, got %s", kind(v))
+		return
+	}
+	mask, err := strconv.ParseUint(n.String(), 10, 64)
+	if err != nil {
+		c.report(pointer, pos, "%s is not a valid mask of flags enum %q", n, e.Name)
+		return
+	}
+	if _, rest := e.FlagNames(mask); rest != 0 {
+		c.report(pointer, pos, "mask %d sets bits that are not members of flags enum %q", mask, e.Name)
+	}
+}
+
+func (c *checker) primitive(p ir.Primitive, v any, pointer string, pos lexer.Position) {
+	var err error
+	switch v := v.(type) {
+	case string:
+		if p.Textual() {
+			err = p.CheckString(v)
+			break
+		}
+		err = fmt.Errorf("expected %s, got string", p.JSONSchema().Type)
+	case json.Number:
+		if p.Numeric() {
+			err = p.CheckNumber(v.String())
+			break
+		}
+		err = fmt.Errorf("expected %s, got number", p.JSONSchema().Type)
+	default:
+		if _, ok := v.(bool); !ok || p != ir.Bool {
+			err = fmt.Errorf("expected %s, got %s", p.JSONSchema().Type, kind(v))
+		}
+	}
+	if err != nil {
+		c.report(pointer, pos, "%s", err)
+	}
+}
+
+// kind returns the JSON type of a decoded value, for messages.
+func kind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// escape escapes an object key for a JSON pointer.
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
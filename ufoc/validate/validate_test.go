@@ -0,0 +1,181 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const contract = `version 1
+namespace Tasks {
+	option wire.case = "snake"
+
+	enum TaskStatus { PENDING RUNNING @alias("ACTIVE") DONE }
+	enum Priority: int { LOW = 1 HIGH = 10 }
+	enum Permission: flags { READ WRITE ADMIN }
+
+	@json.flags("names")
+	enum Role: flags { VIEWER EDITOR }
+
+	type Page<T> { items: T[] }
+
+	type Task {
+		id: uuid
+		title: string
+		status: TaskStatus
+		priority?: Priority
+		permissions?: Permission
+		roles?: Role
+		createdAt: datetime
+		note?: string | null
+		tags?: string[]
+		estimate?: { hours: int32 }
+		subtasks?: Page<Task>
+	}
+}
+`
+
+func load(t *testing.T) *Contract {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.ufoc")
+	require.NoError(t, os.WriteFile(path, []byte(contract), 0o644))
+	c, err := Load(path)
+	require.NoError(t, err)
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	v, err := load(t).Validator("Tasks.Task")
+	require.NoError(t, err)
+
+	const valid = `"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "title": "Ship", "status": "PENDING", "created_at": "2024-05-01T10:00:00Z"`
+
+	tests := []struct {
+		name     string
+		doc      string
+		expected []string
+	}{
+		{
+			name: "valid",
+			doc:  `{` + valid + `, "priority": 10, "permissions": 5, "roles": ["EDITOR"], "note": null, "tags": [], "estimate": {"hours": 2}}`,
+		},
+		{
+			name: "alias",
+			doc:  `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "title": "Ship", "status": "ACTIVE", "created_at": "2024-05-01T10:00:00Z"}`,
+		},
+		{
+			name:     "not an object",
+			doc:      `[]`,
+			expected: []string{`expected object, got array (tasks.ufoc:14:2)`},
+		},
+		{
+			name: "missing and unknown fields",
+			doc:  `{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "createdAt": "2024-05-01T10:00:00Z"}`,
+			expected: []string{
+				`/title: missing required field (tasks.ufoc:16:3)`,
+				`/status: missing required field (tasks.ufoc:17:3)`,
+				`/created_at: missing required field (tasks.ufoc:21:3)`,
+				`/createdAt: unknown field "createdAt" (tasks.ufoc:14:2)`,
+			},
+		},
+		{
+			name: "formats",
+			doc:  `{"id": "42", "title": 42, "status": "PENDING", "created_at": "2024-05-01"}`,
+			expected: []string{
+				`/id: "42" is not a valid uuid (tasks.ufoc:15:3)`,
+				`/title: expected string, got number (tasks.ufoc:16:3)`,
+				`/created_at: "2024-05-01" is not a valid datetime (tasks.ufoc:21:3)`,
+			},
+		},
+		{
+			name: "enums",
+			doc:  `{` + valid + `, "status": "DRAFT", "priority": 2, "permissions": 8, "roles": ["OWNER", 1]}`,
+			expected: []string{
+				`/status: unknown value "DRAFT" of enum "TaskStatus" (tasks.ufoc:17:3)`,
+				`/priority: unknown value "2" of enum "Priority" (tasks.ufoc:18:3)`,
+				`/permissions: mask 8 sets bits that are not members of flags enum "Permission" (tasks.ufoc:19:3)`,
+				`/roles/0: "OWNER" is not a member of flags enum "Role" (tasks.ufoc:20:3)`,
+				`/roles/1: expected string, got number (tasks.ufoc:20:3)`,
+			},
+		},
+		{
+			name: "nested values",
+			doc:  `{` + valid + `, "title": null, "tags": ["a", null], "estimate": {"hours": 1.5, "minutes": 30}, "subtasks": {"items": [{"id": "x"}]}}`,
+			expected: []string{
+				`/title: expected string, got null (tasks.ufoc:16:3)`,
+				`/tags/1: expected string, got null (tasks.ufoc:23:3)`,
+				`/estimate/hours: 1.5 is not an integer (tasks.ufoc:24:16)`,
+				`/estimate/minutes: unknown field "minutes" (tasks.ufoc:24:3)`,
+				`/subtasks/items/0/id: "x" is not a valid uuid (tasks.ufoc:15:3)`,
+				`/subtasks/items/0/title: missing required field (tasks.ufoc:16:3)`,
+				`/subtasks/items/0/status: missing required field (tasks.ufoc:17:3)`,
+				`/subtasks/items/0/created_at: missing required field (tasks.ufoc:21:3)`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := v.Validate([]byte(tt.doc))
+			require.NoError(t, err)
+
+			var got []string
+			for _, violation := range violations {
+				violation.Pos.Filename = filepath.Base(violation.Pos.Filename)
+				got = append(got, violation.String())
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestValidateInvalidJSON(t *testing.T) {
+	v, err := load(t).Validator("Tasks.Task")
+	require.NoError(t, err)
+
+	_, err = v.Validate([]byte(`{"id": `))
+	assert.EqualError(t, err, "invalid JSON: unexpected EOF")
+
+	_, err = v.Validate([]byte(`{} {}`))
+	assert.EqualError(t, err, "invalid JSON: unexpected data after the document")
+}
+
+func TestValidator(t *testing.T) {
+	c := load(t)
+
+	_, err := c.Validator("Task")
+	assert.EqualError(t, err, `type "Task" must be qualified by its namespace, e.g. Tasks.Task`)
+
+	_, err = c.Validator("Tasks.Missing")
+	assert.EqualError(t, err, `unknown type "Tasks.Missing"`)
+
+	_, err = c.Validator("Tasks.Page")
+	assert.EqualError(t, err, `type "Tasks.Page" is generic, validate against a type instantiating it instead`)
+}
+
+func TestLoadErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.ufoc")
+	require.NoError(t, os.WriteFile(path, []byte("version 1\nnamespace Tasks { type Task { id: Missing } }"), 0o644))
+
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), path+" has errors:\n")
+	assert.Contains(t, err.Error(), `unknown type "Missing"`)
+}
+
+func TestLoadBytes(t *testing.T) {
+	c, err := LoadBytes("tasks.ufoc", []byte(contract))
+	require.NoError(t, err)
+	v, err := c.Validator("Tasks.Task")
+	require.NoError(t, err)
+	violations, err := v.Validate([]byte(`{"title": "Ship"}`))
+	require.NoError(t, err)
+	assert.NotEmpty(t, violations)
+
+	_, err = LoadBytes("tasks.ufoc", []byte("version 1\nnamespace Tasks { type Task { id: Missing } }"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown type "Missing"`)
+}